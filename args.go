@@ -0,0 +1,223 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// splits a line of input into tokens.
+// tokens are separated by whitespace and lowercased, except for the parts
+// wrapped in single or double quotes which are kept as typed (so nicknames
+// keep their case and can contain spaces). inside double quotes a backslash
+// escapes the next character.
+func tokenize(text string) ([]string, error) {
+	tokens, _, err := splitLine(text)
+	return tokens, err
+}
+
+// splitLine is tokenize that also hands back every token exactly as it was
+// typed, for the args that keep their case like file paths
+func splitLine(text string) (tokens, raw []string, err error) {
+	tokens = []string{}
+	raw = []string{}
+	current := strings.Builder{}
+	currentRaw := strings.Builder{}
+	inToken := false
+	var quote rune // the quote we're inside of, 0 when not quoted
+	escaped := false
+
+	for _, r := range text {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			currentRaw.WriteRune(r)
+			escaped = false
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0:
+			current.WriteRune(r)
+			currentRaw.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inToken = true
+		case unicode.IsSpace(r):
+			if inToken {
+				tokens = append(tokens, current.String())
+				raw = append(raw, currentRaw.String())
+				current.Reset()
+				currentRaw.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(unicode.ToLower(r))
+			currentRaw.WriteRune(r)
+			inToken = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, nil, errors.New("unterminated quote")
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+		raw = append(raw, currentRaw.String())
+	}
+	return tokens, raw, nil
+}
+
+// shellTokens normalises args the shell has already split and unquoted the
+// same way tokenize does a line typed in the repl. there's no telling what
+// was quoted any more, so only the args a command keeps the case of do.
+func shellTokens(args []string) (tokens, raw []string) {
+	tokens = make([]string, 0, len(args))
+	for _, arg := range args {
+		tokens = append(tokens, strings.ToLower(arg))
+	}
+	return tokens, args
+}
+
+// returned for a line whose first word isn't a command
+var errUnknownCommand = errors.New("Unknown command")
+
+// usageError is returned when a command is called with the wrong arguments
+type usageError struct {
+	cmd    cliCommand
+	reason string
+}
+
+func (e usageError) Error() string {
+	return fmt.Sprintf("%v\nusage: %v", e.reason, e.cmd.synopsis())
+}
+
+// synopsis is the one line usage of a command, e.g. "explore <area>"
+func (cmd cliCommand) synopsis() string {
+	parts := []string{cmd.name}
+	if cmd.usage != "" {
+		parts = append(parts, cmd.usage)
+	}
+	names := make([]string, 0, len(cmd.flags))
+	for name := range cmd.flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if cmd.flags[name] {
+			parts = append(parts, fmt.Sprintf("[--%v <value>]", name))
+		} else {
+			parts = append(parts, fmt.Sprintf("[--%v]", name))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// checks args against what cmd accepts and returns them normalised: flags
+// that take a value always come out as --name=value and flags without one
+// as --name, in the order they were given, mixed with the positional args.
+// raw is args as typed, the positional args in cmd.keepCase come from it.
+func (cmd cliCommand) parseArgs(args, raw []string) ([]string, error) {
+	normalised := []string{}
+	positional := 0
+	addPositional := func(i int) {
+		if slices.Contains(cmd.keepCase, positional) {
+			normalised = append(normalised, raw[i])
+		} else {
+			normalised = append(normalised, args[i])
+		}
+		positional++
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			// everything after -- is positional, even if it starts with dashes
+			normalised = append(normalised, arg)
+			for j := i + 1; j < len(args); j++ {
+				addPositional(j)
+			}
+			break
+		}
+		if !strings.HasPrefix(arg, "--") || len(arg) == 2 {
+			addPositional(i)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(arg[2:], "=")
+		takesValue, ok := cmd.flags[name]
+		switch {
+		case !ok:
+			return nil, usageError{cmd, fmt.Sprintf("unknown flag --%v", name)}
+		case !takesValue && hasValue:
+			return nil, usageError{cmd, fmt.Sprintf("flag --%v does not take a value", name)}
+		case !takesValue:
+			normalised = append(normalised, "--"+name)
+		case hasValue:
+			normalised = append(normalised, "--"+name+"="+value)
+		case i+1 < len(args):
+			i++
+			normalised = append(normalised, "--"+name+"="+args[i])
+		default:
+			return nil, usageError{cmd, fmt.Sprintf("flag --%v needs a value", name)}
+		}
+	}
+
+	if positional < cmd.minArgs {
+		return nil, usageError{cmd, "not enough arguments"}
+	}
+	if cmd.maxArgs >= 0 && positional > cmd.maxArgs {
+		return nil, usageError{cmd, "too many arguments"}
+	}
+	return normalised, nil
+}
+
+// splits args already checked by parseArgs back into positional args and
+// flags. flags without a value map to "true".
+func splitFlags(args []string) ([]string, map[string]string) {
+	positional := []string{}
+	flags := make(map[string]string)
+	for i, arg := range args {
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "--") || len(arg) == 2 {
+			positional = append(positional, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(arg[2:], "=")
+		if !hasValue {
+			value = "true"
+		}
+		flags[name] = value
+	}
+	return positional, flags
+}
+
+// runs one line of input against the registry of commands
+func runLine(conf *config, commands map[string]cliCommand, line string) error {
+	tokens, raw, err := splitLine(line)
+	if err != nil {
+		return err
+	}
+	return runTokens(conf, commands, tokens, raw)
+}
+
+// runs a command that has already been split into words, the first one
+// naming the command. raw holds the same words as they were typed.
+func runTokens(conf *config, commands map[string]cliCommand, tokens, raw []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	cmd, ok := commands[tokens[0]]
+	if !ok {
+		return errUnknownCommand
+	}
+	args, err := cmd.parseArgs(tokens[1:], raw[1:])
+	if err != nil {
+		return err
+	}
+	return cmd.callback(conf, args...)
+}
@@ -0,0 +1,471 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lulock/pokedex/internal/bag"
+	"github.com/lulock/pokedex/internal/battle"
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/encounter"
+	"github.com/lulock/pokedex/internal/growth"
+	"github.com/lulock/pokedex/internal/learnset"
+	"github.com/lulock/pokedex/internal/pokeapi"
+	"github.com/lulock/pokedex/internal/typechart"
+)
+
+var (
+	// returned by commands that can't be used in the middle of a battle
+	errInBattle = errors.New("you're in a battle, fight or run first")
+	// returned by battle commands when there's no battle going on
+	errNoBattle = errors.New("you're not in a battle, start one with battle")
+)
+
+// the trainers you can run into, one is picked at random
+var trainers = []string{"Youngster Joey", "Lass Dana", "Bug Catcher Rick", "Hiker Alan", "Swimmer Tess"}
+
+// paid per level of a defeated trainer's pokemon
+const prizeMoney = 40
+
+// battleState is the battle going on in the session. side 0 is always the
+// trainer's lead pokemon, side 1 the foe.
+type battleState struct {
+	battle  *battle.Battle
+	lead    int    // id of the owned pokemon fighting
+	trainer string // empty for wild battles
+	foe     encounter.Wild
+	// what the foe is worth once defeated
+	foeBaseExperience int
+}
+
+type battleMoveDoc struct {
+	Name  string `json:"name"`
+	PP    int    `json:"pp"`
+	MaxPP int    `json:"max_pp"`
+}
+
+type combatantDoc struct {
+	Name  string          `json:"name"`
+	Level int             `json:"level"`
+	HP    int             `json:"hp"`
+	MaxHP int             `json:"max_hp"`
+	Types []string        `json:"types"`
+	Moves []battleMoveDoc `json:"moves,omitempty"`
+}
+
+type battleEventDoc struct {
+	Attacker      string  `json:"attacker"`
+	Defender      string  `json:"defender"`
+	Move          string  `json:"move"`
+	Missed        bool    `json:"missed,omitempty"`
+	NoEffect      bool    `json:"no_effect,omitempty"`
+	Critical      bool    `json:"critical,omitempty"`
+	Effectiveness float64 `json:"effectiveness"`
+	Damage        int     `json:"damage"`
+	Recoil        int     `json:"recoil,omitempty"`
+	Fainted       bool    `json:"fainted,omitempty"`
+	AttackerDown  bool    `json:"attacker_fainted,omitempty"`
+}
+
+type battleDoc struct {
+	Trainer string           `json:"trainer,omitempty"`
+	Turn    int              `json:"turn"`
+	Pokemon combatantDoc     `json:"pokemon"`
+	Foe     combatantDoc     `json:"foe"`
+	Events  []battleEventDoc `json:"events"`
+	Over    bool             `json:"over"`
+	Won     bool             `json:"won"`
+	Ran     bool             `json:"ran,omitempty"`
+	Prize   int              `json:"prize,omitempty"`
+	LevelUp *levelUpDoc      `json:"level_up,omitempty"`
+}
+
+func newCombatantDoc(c *battle.Combatant, withMoves bool) combatantDoc {
+	doc := combatantDoc{Name: c.Name, Level: c.Level, HP: c.HP, MaxHP: c.Stats.HP, Types: c.Types}
+	if withMoves {
+		for i, move := range c.Moves {
+			doc.Moves = append(doc.Moves, battleMoveDoc{Name: move.Name, PP: c.PP[i], MaxPP: move.PP})
+		}
+	}
+	return doc
+}
+
+func newBattleDoc(state *battleState) battleDoc {
+	b := state.battle
+	return battleDoc{
+		Trainer: state.trainer,
+		Turn:    b.Turns,
+		Pokemon: newCombatantDoc(b.Sides[0], true),
+		Foe:     newCombatantDoc(b.Sides[1], false),
+		Events:  []battleEventDoc{},
+	}
+}
+
+func newBattleEventDocs(events ...battle.Event) []battleEventDoc {
+	docs := []battleEventDoc{}
+	for _, e := range events {
+		docs = append(docs, battleEventDoc{
+			Attacker:      e.Attacker,
+			Defender:      e.Defender,
+			Move:          e.Move,
+			Missed:        e.Missed,
+			NoEffect:      e.NoEffect,
+			Critical:      e.Critical,
+			Effectiveness: e.Effectiveness,
+			Damage:        e.Damage,
+			Recoil:        e.Recoil,
+			Fainted:       e.Fainted,
+			AttackerDown:  e.AttackerDown,
+		})
+	}
+	return docs
+}
+
+// builds a combatant from a pokemon's species data and the moves it knows
+func newCombatant(conf *config, pokemon pokeapi.Pokemon, name string, level int, moveNames []string) (*battle.Combatant, error) {
+	base := battle.Stats{
+		HP:             baseStat(pokemon, "hp"),
+		Attack:         baseStat(pokemon, "attack"),
+		Defense:        baseStat(pokemon, "defense"),
+		SpecialAttack:  baseStat(pokemon, "special-attack"),
+		SpecialDefense: baseStat(pokemon, "special-defense"),
+		Speed:          baseStat(pokemon, "speed"),
+	}
+	types := pokemonTypes(pokemon)
+	moves := []battle.Move{}
+	for _, moveName := range moveNames {
+		move, err := conf.Client.GetMove(moveName)
+		if err != nil {
+			return nil, err
+		}
+		moves = append(moves, battle.Move{
+			Name:     move.Name,
+			Type:     move.Type.Name,
+			Class:    move.DamageClass.Name,
+			Power:    move.Power,
+			Accuracy: move.Accuracy,
+			PP:       move.PP,
+			Priority: move.Priority,
+		})
+	}
+	return battle.NewCombatant(name, level, types, base, moves), nil
+}
+
+// the type chart for every move type either side can use
+func battleTypeChart(conf *config, sides ...*battle.Combatant) (*typechart.Chart, error) {
+	attacking := []string{}
+	for _, side := range sides {
+		for _, move := range side.Moves {
+			attacking = append(attacking, move.Type)
+		}
+	}
+	return loadTypes(conf, attacking...)
+}
+
+// the move the foe uses this turn: trainers pick the best one, wild pokemon
+// any old move
+func foeMove(state *battleState) int {
+	if state.trainer != "" {
+		return state.battle.BestMove(1)
+	}
+	return state.battle.RandomMove(1)
+}
+
+// battle command starts a battle between the lead pokemon and the wild
+// pokemon in front of the trainer, or a new one from the area. --trainer
+// battles a trainer instead.
+func commandBattle(conf *config, args ...string) error {
+	_, flags := splitFlags(args)
+	if conf.Battle != nil {
+		return errInBattle
+	}
+	lead := leadPokemon(conf)
+	if lead == nil {
+		return errors.New("you don't have any pokemon to battle with, catch one first")
+	}
+
+	trainer := ""
+	var foe encounter.Wild
+	if flags["trainer"] != "" || conf.Wild == nil {
+		table, err := currentTable(conf)
+		if err != nil {
+			return err
+		}
+		wild, ok := table.Draw(conf.Rand)
+		if !ok {
+			return fmt.Errorf("there are no pokemon around %v", conf.Location)
+		}
+		foe = wild
+	} else {
+		foe = *conf.Wild
+	}
+	if flags["trainer"] != "" {
+		// trainers bring a pokemon as strong as yours
+		trainer = trainers[conf.Rand.Intn(len(trainers))]
+		foe.Level = lead.Level
+	}
+
+	leadData, err := speciesData(conf, lead.Species)
+	if err != nil {
+		return err
+	}
+	if len(lead.Moves) == 0 {
+		// pokemon from older saves learn their moves now
+		learnMoves(lead, learnset.Between(learnset.LevelUp(leadData, conf.VersionGroup), 0, lead.Level))
+	}
+	own, err := newCombatant(conf, leadData, lead.Name(), lead.Level, lead.Moves)
+	if err != nil {
+		return err
+	}
+	foeData, err := conf.Client.GetPokemon(foe.Pokemon)
+	if err != nil {
+		return err
+	}
+	foeMoves := collection.Owned{}
+	learnMoves(&foeMoves, learnset.Between(learnset.LevelUp(foeData, conf.VersionGroup), 0, foe.Level))
+	other, err := newCombatant(conf, foeData, foe.Pokemon, foe.Level, foeMoves.Moves)
+	if err != nil {
+		return err
+	}
+	chart, err := battleTypeChart(conf, own, other)
+	if err != nil {
+		return err
+	}
+
+	if trainer == "" {
+		conf.Wild = &foe
+	}
+	conf.Battle = &battleState{
+		battle:            battle.New(own, other, chart, conf.Rand),
+		lead:              lead.ID,
+		trainer:           trainer,
+		foe:               foe,
+		foeBaseExperience: foeData.BaseExperience,
+	}
+	doc := newBattleDoc(conf.Battle)
+	return conf.emit(doc, func() {
+		if trainer != "" {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("%v wants to battle!", trainer))
+			fmt.Fprintln(conf.Out, fmt.Sprintf("%v sent out %v (Lv. %v)!", trainer, foe.Pokemon, foe.Level))
+		} else {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("A wild %v (Lv. %v) wants to battle!", foe.Pokemon, foe.Level))
+		}
+		fmt.Fprintln(conf.Out, fmt.Sprintf("Go! %v!", own.Name))
+		printBattleStatus(conf, doc)
+	})
+}
+
+// fight command plays one turn of the battle with the given move, or the
+// best one the lead knows when no move is given
+func commandFight(conf *config, args ...string) error {
+	state := conf.Battle
+	if state == nil {
+		return errNoBattle
+	}
+	own := state.battle.Sides[0]
+	move := state.battle.BestMove(0)
+	if len(args) > 0 {
+		move = own.MoveIndex(args[0])
+		if move < 0 {
+			return fmt.Errorf("%v doesn't know %v", own.Name, args[0])
+		}
+		if own.PP[move] == 0 {
+			return fmt.Errorf("there's no PP left for %v", args[0])
+		}
+	}
+
+	events := state.battle.Turn(move, foeMove(state))
+	doc := newBattleDoc(state)
+	doc.Events = newBattleEventDocs(events...)
+	if err := settleBattle(conf, &doc); err != nil {
+		return err
+	}
+	return conf.emit(doc, func() {
+		printBattle(conf, doc)
+	})
+}
+
+// run command flees from a wild battle
+func commandRun(conf *config, args ...string) error {
+	state := conf.Battle
+	if state == nil {
+		return errNoBattle
+	}
+	if state.trainer != "" {
+		return errors.New("there's no running from a trainer battle!")
+	}
+	doc := newBattleDoc(state)
+	doc.Over, doc.Ran = true, true
+	conf.Battle = nil
+	conf.Wild = nil
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, "Got away safely!")
+	})
+}
+
+type useDoc struct {
+	Item    string    `json:"item"`
+	Pokemon string    `json:"pokemon"`
+	Healed  int       `json:"healed"`
+	Battle  battleDoc `json:"battle"`
+}
+
+// use command gives a potion to the lead in the middle of a battle. it takes
+// up the turn, so the foe attacks afterwards.
+func commandUse(conf *config, args ...string) error {
+	state := conf.Battle
+	if state == nil {
+		return errNoBattle
+	}
+	item, err := bag.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	if item.Kind != bag.Potion {
+		return fmt.Errorf("%v can't be used on your pokemon, throw balls with catch", item.Name)
+	}
+	if conf.Bag.Count(item.Name) == 0 {
+		return fmt.Errorf("%w: you're out of %v", bag.ErrOutOfItem, item.Name)
+	}
+	own := state.battle.Sides[0]
+	if own.HP >= own.Stats.HP {
+		return fmt.Errorf("%v is already at full health", own.Name)
+	}
+	conf.Bag.Take(item.Name)
+	healed := own.Heal(item.Heal)
+
+	events := newBattleEventDocs(state.battle.Act(1, foeMove(state)))
+	doc := useDoc{Item: item.Name, Pokemon: own.Name, Healed: healed, Battle: newBattleDoc(state)}
+	doc.Battle.Events = events
+	if err := settleBattle(conf, &doc.Battle); err != nil {
+		return err
+	}
+	// the potion is gone either way
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your bag: %w", err)
+	}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("You used a %v on %v.", doc.Item, doc.Pokemon))
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v recovered %v HP.", doc.Pokemon, doc.Healed))
+		printBattle(conf, doc.Battle)
+	})
+}
+
+// ends the battle when one side fainted, handing out experience and prize
+// money when the trainer won, and fills doc in with how it went
+func settleBattle(conf *config, doc *battleDoc) error {
+	state := conf.Battle
+	if !state.battle.Over() {
+		return nil
+	}
+	conf.Battle = nil
+	if state.trainer == "" {
+		conf.Wild = nil
+	}
+	doc.Over = true
+	doc.Won = state.battle.Winner == 0
+	if doc.Won {
+		conf.Stats.BattlesWon++
+		lead, err := conf.Owned.Get(state.lead)
+		if err != nil {
+			return err
+		}
+		gained := growth.Yield(state.foeBaseExperience, state.foe.Level)
+		if state.trainer != "" {
+			// trainer battles are worth half as much again
+			gained = gained * 3 / 2
+			doc.Prize = state.foe.Level * prizeMoney
+			conf.Bag.Money += doc.Prize
+			conf.Stats.MoneyEarned += doc.Prize
+		}
+		levelUp, err := gainExperience(conf, lead, gained)
+		if err != nil {
+			return err
+		}
+		doc.LevelUp = &levelUp
+	} else {
+		conf.Stats.BattlesLost++
+	}
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your pokedex: %w", err)
+	}
+	return nil
+}
+
+// prints the turn, and how the battle ended or where it stands
+func printBattle(conf *config, doc battleDoc) {
+	for _, event := range doc.Events {
+		printBattleEvent(conf, event)
+	}
+	switch {
+	case !doc.Over:
+		printBattleStatus(conf, doc)
+	case doc.Won:
+		if doc.Trainer != "" {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("You defeated %v!", doc.Trainer))
+			fmt.Fprintln(conf.Out, fmt.Sprintf("You got ₽%v for winning!", doc.Prize))
+		}
+		if doc.LevelUp != nil {
+			printLevelUp(conf, *doc.LevelUp)
+		}
+	default:
+		fmt.Fprintln(conf.Out, "You lost the battle... you hurry back to safety.")
+	}
+}
+
+func printBattleEvent(conf *config, e battleEventDoc) {
+	fmt.Fprintln(conf.Out, fmt.Sprintf("%v used %v!", e.Attacker, e.Move))
+	switch {
+	case e.Missed:
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v's attack missed!", e.Attacker))
+	case e.Effectiveness == 0:
+		fmt.Fprintln(conf.Out, fmt.Sprintf("It doesn't affect %v...", e.Defender))
+	case e.NoEffect:
+		fmt.Fprintln(conf.Out, "But nothing happened!")
+	default:
+		if e.Critical {
+			fmt.Fprintln(conf.Out, "A critical hit!")
+		}
+		if e.Effectiveness > 1 {
+			fmt.Fprintln(conf.Out, "It's super effective!")
+		} else if e.Effectiveness < 1 {
+			fmt.Fprintln(conf.Out, "It's not very effective...")
+		}
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v took %v damage.", e.Defender, e.Damage))
+		if e.Recoil > 0 {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("%v is hit with recoil!", e.Attacker))
+		}
+	}
+	if e.Fainted {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v fainted!", e.Defender))
+	}
+	if e.AttackerDown {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v fainted!", e.Attacker))
+	}
+}
+
+func printBattleStatus(conf *config, doc battleDoc) {
+	foe := doc.Foe.Name
+	if doc.Trainer == "" {
+		foe = "Wild " + foe
+	}
+	fmt.Fprintln(conf.Out, fmt.Sprintf("%v Lv. %v HP %v/%v | %v Lv. %v HP %v/%v",
+		doc.Pokemon.Name, doc.Pokemon.Level, doc.Pokemon.HP, doc.Pokemon.MaxHP,
+		foe, doc.Foe.Level, doc.Foe.HP, doc.Foe.MaxHP))
+	fmt.Fprintln(conf.Out, "Moves:")
+	for _, move := range doc.Pokemon.Moves {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("  . %v (%v/%v PP)", move.Name, move.PP, move.MaxPP))
+	}
+}
+
+// moves the lead can use in the current battle
+func completeBattleMoves(conf *config) []string {
+	if conf.Battle == nil {
+		return nil
+	}
+	names := []string{}
+	for _, move := range conf.Battle.battle.Sides[0].Moves {
+		names = append(names, move.Name)
+	}
+	return names
+}
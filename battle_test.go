@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lulock/pokedex/internal/bag"
+	"github.com/lulock/pokedex/internal/collection"
+)
+
+func TestWildBattle(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	conf.Location = "eterna-city-area"
+
+	if err := runLine(conf, commands, "battle"); err == nil {
+		t.Errorf("expected an error without any pokemon")
+	}
+	if err := runLine(conf, commands, "fight"); !errors.Is(err, errNoBattle) {
+		t.Errorf("expected errNoBattle, got %v", err)
+	}
+
+	conf.Owned.Add(collection.Owned{Species: "pikachu", PokemonID: 25, Nickname: "Sparky", Level: 10, Experience: 1000,
+		Moves: []string{"thunder-shock", "growl"}})
+	if err := runLine(conf, commands, "battle"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if !strings.HasPrefix(lines[0], "A wild psyduck (Lv. ") || lines[1] != "Go! Sparky!" {
+		t.Errorf("unexpected start of battle: %q", lines)
+	}
+	if conf.Wild == nil || conf.Wild.Pokemon != "psyduck" {
+		t.Errorf("expected the foe to be the wild pokemon, got %+v", conf.Wild)
+	}
+	out.Reset()
+
+	if err := runLine(conf, commands, "battle"); !errors.Is(err, errInBattle) {
+		t.Errorf("expected errInBattle, got %v", err)
+	}
+	if err := runLine(conf, commands, "encounter"); !errors.Is(err, errInBattle) {
+		t.Errorf("expected errInBattle, got %v", err)
+	}
+	if err := runLine(conf, commands, "fight surf"); err == nil || err.Error() != "Sparky doesn't know surf" {
+		t.Errorf("expected an error for an unknown move, got %v", err)
+	}
+
+	for i := 0; i < 20 && conf.Battle != nil; i++ {
+		if err := runLine(conf, commands, "fight thunder-shock"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if conf.Battle != nil || conf.Wild != nil {
+		t.Fatalf("expected the battle to be over")
+	}
+	text := out.String()
+	if !strings.Contains(text, "Sparky used thunder-shock!") || !strings.Contains(text, "It's super effective!") ||
+		!strings.Contains(text, "psyduck fainted!") || !strings.Contains(text, "Sparky gained ") {
+		t.Errorf("unexpected battle: %q", text)
+	}
+	if lead, _ := conf.Owned.Get(1); lead.Experience <= 1000 {
+		t.Errorf("expected the lead to earn experience, got %+v", lead)
+	}
+}
+
+func TestTrainerBattle(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	conf.Location = "canalave-city-area"
+	conf.Owned.Add(collection.Owned{Species: "pikachu", PokemonID: 25, Level: 12, Experience: 1728,
+		Moves: []string{"thunder-shock", "quick-attack"}})
+	money := conf.Bag.Money
+
+	if err := runLine(conf, commands, "set output json"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out.Reset()
+	if err := runLine(conf, commands, "battle --trainer"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := battleDoc{}
+	if err := json.Unmarshal(out.Bytes(), &start); err != nil {
+		t.Fatalf("expected a JSON document, got %q: %v", out.String(), err)
+	}
+	if start.Trainer == "" || start.Foe.Level != 12 || start.Pokemon.HP != start.Pokemon.MaxHP || len(start.Pokemon.Moves) != 2 {
+		t.Errorf("unexpected start of battle: %+v", start)
+	}
+	out.Reset()
+
+	if err := runLine(conf, commands, "run"); err == nil {
+		t.Errorf("expected not to be able to run from a trainer")
+	}
+	if err := runLine(conf, commands, "catch"); err == nil {
+		t.Errorf("expected not to be able to catch a trainer's pokemon")
+	}
+
+	last := battleDoc{}
+	for i := 0; i < 30 && conf.Battle != nil; i++ {
+		out.Reset()
+		if err := runLine(conf, commands, "fight"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := json.Unmarshal(out.Bytes(), &last); err != nil {
+			t.Fatalf("expected a JSON document, got %q: %v", out.String(), err)
+		}
+	}
+	if !last.Over {
+		t.Fatalf("expected the battle to be over, got %+v", last)
+	}
+	if last.Won && (last.Prize != 12*prizeMoney || conf.Bag.Money != money+last.Prize || last.LevelUp == nil) {
+		t.Errorf("expected a prize and experience for winning, got %+v", last)
+	}
+	if !last.Won && conf.Bag.Money != money {
+		t.Errorf("expected no prize for losing, got %+v", last)
+	}
+	if last.Won && (conf.Stats.BattlesWon != 1 || conf.Stats.MoneyEarned != last.Prize) {
+		t.Errorf("expected the win to be counted, got %+v", conf.Stats)
+	}
+	if !last.Won && conf.Stats.BattlesLost != 1 {
+		t.Errorf("expected the loss to be counted, got %+v", conf.Stats)
+	}
+}
+
+func TestCatchDuringBattle(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	conf.Location = "pastoria-city-area"
+	conf.Bag = bag.Bag{Items: map[string]int{"poke-ball": 20}}
+	conf.Owned.Add(collection.Owned{Species: "pikachu", PokemonID: 25, Level: 30, Experience: 27000,
+		Moves: []string{"growl"}})
+
+	if err := runLine(conf, commands, "battle"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	foe := conf.Battle.foe.Pokemon
+	other := "magikarp"
+	if foe == other {
+		other = "pikachu"
+	}
+	if err := runLine(conf, commands, "catch "+other); err == nil {
+		t.Errorf("expected not to be able to catch %v while battling %v", other, foe)
+	}
+	out.Reset()
+
+	for i := 0; i < 20 && conf.Battle != nil; i++ {
+		if err := runLine(conf, commands, "catch"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if conf.Battle != nil || len(conf.Owned.Pokemon) != 2 || conf.Owned.Pokemon[1].Species != foe {
+		t.Errorf("expected %v to be caught and the battle to be over, got %q", foe, out.String())
+	}
+}
+
+func TestUsePotion(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	conf.Location = "eterna-city-area"
+	conf.Bag = bag.Bag{Items: map[string]int{"potion": 1, "poke-ball": 1}}
+	conf.Owned.Add(collection.Owned{Species: "pikachu", PokemonID: 25, Nickname: "Sparky", Level: 30, Experience: 27000,
+		Moves: []string{"growl"}})
+
+	if err := runLine(conf, commands, "use potion"); !errors.Is(err, errNoBattle) {
+		t.Errorf("expected errNoBattle, got %v", err)
+	}
+	if err := runLine(conf, commands, "battle"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := runLine(conf, commands, "use potion"); err == nil || !strings.Contains(err.Error(), "full health") {
+		t.Errorf("expected a pokemon at full health not to use the potion, got %v", err)
+	}
+	if err := runLine(conf, commands, "use poke-ball"); err == nil {
+		t.Errorf("expected balls not to be usable")
+	}
+	out.Reset()
+
+	own := conf.Battle.battle.Sides[0]
+	own.HP -= 30
+	hp := own.HP
+	if err := runLine(conf, commands, "use potion"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "You used a potion on Sparky.\nSparky recovered 20 HP.\n") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+	// the foe gets its turn after the potion
+	if own.HP > hp+20 || conf.Bag.Count("potion") != 0 || !strings.Contains(out.String(), "psyduck used") {
+		t.Errorf("expected 20 HP back and the foe to act, got %v/%v and %q", own.HP, own.Stats.HP, out.String())
+	}
+	if err := runLine(conf, commands, "use potion"); !errors.Is(err, bag.ErrOutOfItem) {
+		t.Errorf("expected ErrOutOfItem, got %v", err)
+	}
+}
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lulock/pokedex/internal/bag"
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/encounter"
+	"github.com/lulock/pokedex/internal/fakeapi"
+	"github.com/lulock/pokedex/internal/learnset"
+	"github.com/lulock/pokedex/internal/pokeapi"
+	"github.com/lulock/pokedex/internal/pokecache"
+	"github.com/lulock/pokedex/internal/save"
+)
+
+// newTestConfig wires a config to a fresh fake PokeAPI and captures output
+func newTestConfig(t *testing.T) (*config, *bytes.Buffer, *fakeapi.Server) {
+	t.Helper()
+	api := fakeapi.New()
+	t.Cleanup(api.Close)
+	cache := pokecache.NewCache(time.Minute)
+	t.Cleanup(cache.Close)
+
+	client := pokeapi.NewClient(api.BaseURL(), api.Client(), cache)
+	out := &bytes.Buffer{}
+	conf := &config{
+		Next:    client.BaseURL() + "/location-area/",
+		Client:  client,
+		Pokedex: make(map[string]pokeapi.Pokemon),
+		Out:     out,
+		Rand:    rand.New(rand.NewSource(1)),
+
+		SeenAreas: make(map[string]bool),
+		Output:    outputText,
+		Bag:       bag.New(),
+
+		VersionGroup: learnset.DefaultVersionGroup,
+	}
+	return conf, out, api
+}
+
+func expectLines(t *testing.T, out *bytes.Buffer, expected ...string) {
+	t.Helper()
+	actual := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected: %q, but got %q.", expected, actual)
+	}
+	for i := range actual {
+		if actual[i] != expected[i] {
+			t.Errorf("Expected: %q, but got: %q.", expected[i], actual[i])
+		}
+	}
+	out.Reset()
+}
+
+func TestCommandMapPaging(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+
+	if err := commandMapb(conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "you're on the first page")
+
+	if err := commandMap(conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "canalave-city-area", "eterna-city-area")
+
+	if err := commandMap(conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "pastoria-city-area")
+
+	if err := commandMap(conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "you're on the last page")
+
+	if err := commandMapb(conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "canalave-city-area", "eterna-city-area")
+}
+
+func TestCommandExplore(t *testing.T) {
+	conf, out, api := newTestConfig(t)
+
+	for i := 0; i < 2; i++ {
+		if err := commandExplore(conf, "canalave-city-area"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expectLines(t, out,
+			"Looking around canalave-city-area for pokemon 🧐",
+			"Found these fellas:",
+			". tentacool",
+			". pikachu",
+		)
+	}
+	if n := api.Requests("/location-area/canalave-city-area"); n != 1 {
+		t.Errorf("expected the second explore to hit the cache, got %v requests", n)
+	}
+
+	err := commandExplore(conf, "nowhere")
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCommandCatch(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	conf.Bag.Add(bag.DefaultBall, 100)
+	money := conf.Bag.Money
+
+	if err := commandCatch(conf, "pikachu"); !errors.Is(err, errNoLocation) {
+		t.Errorf("expected errNoLocation before exploring, got %v", err)
+	}
+	if err := commandExplore(conf, "canalave-city-area"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out.Reset()
+
+	caught := false
+	for i := 0; i < 50 && !caught; i++ {
+		if err := commandCatch(conf, "pikachu"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		caught = strings.Contains(out.String(), "pikachu was caught!")
+		if !caught && !strings.Contains(out.String(), "pikachu escaped!") {
+			t.Fatalf("unexpected output: %q", out.String())
+		}
+		out.Reset()
+	}
+	if !caught {
+		t.Fatalf("expected pikachu to be caught eventually")
+	}
+	if _, ok := conf.Pokedex["pikachu"]; !ok {
+		t.Errorf("expected pikachu in the pokedex")
+	}
+	owned, err := conf.Owned.Get(1)
+	if err != nil || owned.Species != "pikachu" || owned.PokemonID != 25 || owned.CaughtIn != "canalave-city-area" || owned.CaughtAt.IsZero() {
+		t.Errorf("expected the caught pikachu in the collection, got %+v %v", owned, err)
+	}
+	if conf.Bag.Money != money+112 {
+		t.Errorf("expected a reward of 112 for pikachu, got %v", conf.Bag.Money-money)
+	}
+
+	err = commandCatch(conf, "psyduck")
+	if err == nil || err.Error() != "there are no psyduck around canalave-city-area" {
+		t.Errorf("expected psyduck not to be around, got %v", err)
+	}
+	if _, ok := conf.Pokedex["psyduck"]; ok {
+		t.Errorf("expected psyduck not to be caught")
+	}
+}
+
+func TestCommandInspect(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+
+	if err := commandInspect(conf, "pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "you have not caught that pokemon")
+
+	pikachu, err := conf.Client.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conf.Pokedex["pikachu"] = pikachu
+	if err := commandInspect(conf, "pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out,
+		"Name: pikachu",
+		"Height: 4",
+		"Weight: 60",
+		"Stats:",
+		"  . hp: 35",
+		"  . attack: 55",
+		"  . defense: 40",
+		"  . special-attack: 50",
+		"  . special-defense: 50",
+		"  . speed: 90",
+		"Types:",
+		"  . electric",
+	)
+}
+
+func TestCommandPokedex(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+
+	if err := commandPokedex(conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "You haven't caught any Pokemon yet! Use the Catch command and try to catch 'em all.")
+
+	conf.Pokedex["pikachu"] = pokeapi.Pokemon{Name: "pikachu"}
+	conf.Owned.Add(collection.Owned{Species: "pikachu", Level: 5})
+	conf.Owned.Add(collection.Owned{Species: "pikachu", Nickname: "Sparky", Level: 7, Shiny: true})
+	if err := commandPokedex(conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Your Pokedex:", " . #1 pikachu Lv. 5", " . #2 Sparky (pikachu) ✨ Lv. 7")
+}
+
+func TestInspectOwned(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+
+	if err := runLine(conf, commands, "inspect 1"); !errors.Is(err, collection.ErrNotOwned) {
+		t.Errorf("expected ErrNotOwned, got %v", err)
+	}
+
+	// the species data is fetched when the pokedex doesn't have it
+	caughtAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	conf.Owned.Add(collection.Owned{Species: "pikachu", PokemonID: 25, Nickname: "Sparky", Level: 5, CaughtAt: caughtAt, CaughtIn: "canalave-city-area"})
+	if err := runLine(conf, commands, "inspect #1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out,
+		"ID: #1",
+		"Nickname: Sparky",
+		"Level: 5",
+		"Experience: 0",
+		"Party: slot 1",
+		"Caught: 2024-05-01 in canalave-city-area",
+		"Name: pikachu",
+		"Height: 4",
+		"Weight: 60",
+		"Stats:",
+		"  . hp: 35",
+		"  . attack: 55",
+		"  . defense: 40",
+		"  . special-attack: 50",
+		"  . special-defense: 50",
+		"  . speed: 90",
+		"Types:",
+		"  . electric",
+	)
+
+	conf.Owned.Add(collection.Owned{Species: "pikachu", PokemonID: 25, Level: 9})
+	if err := runLine(conf, commands, "inspect pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	expected := []string{"Owned:", "  . #1 Sparky (pikachu) Lv. 5", "  . #2 pikachu Lv. 9"}
+	if tail := lines[len(lines)-3:]; strings.Join(tail, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected: %q, but got %q.", expected, tail)
+	}
+}
+
+func TestNicknameAndRelease(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	conf.SavePath = filepath.Join(t.TempDir(), "save.json")
+	conf.Owned.Add(collection.Owned{Species: "pikachu", Level: 5})
+	conf.Owned.Add(collection.Owned{Species: "psyduck", Level: 3})
+
+	if err := runLine(conf, commands, `nickname 1 "Sparky"`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "#1 pikachu is now called Sparky.")
+	if err := runLine(conf, commands, "nickname 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "#1 is just called pikachu again.")
+	if err := runLine(conf, commands, `nickname 1 "Much Too Long A Name"`); err == nil {
+		t.Errorf("expected an error for a long nickname")
+	}
+	if err := runLine(conf, commands, "nickname pikachu sparky"); err == nil {
+		t.Errorf("expected an error for a species name instead of an id")
+	}
+
+	if err := runLine(conf, commands, "release 2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "#2 psyduck Lv. 3 was released back into the wild. Bye, psyduck!")
+	if err := runLine(conf, commands, "release 2"); !errors.Is(err, collection.ErrNotOwned) {
+		t.Errorf("expected ErrNotOwned, got %v", err)
+	}
+	if err := runLine(conf, commands, "release 1"); !errors.Is(err, collection.ErrLastInParty) {
+		t.Errorf("expected ErrLastInParty, got %v", err)
+	}
+
+	saved, err := save.Load(conf.SavePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(saved.Owned.Pokemon) != 1 || saved.Owned.Pokemon[0].Species != "pikachu" || saved.Owned.NextID != 3 {
+		t.Errorf("expected the release to be saved, got %+v", saved.Owned)
+	}
+}
+
+func TestJSONOutput(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+
+	if err := runLine(conf, commands, "set output json"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, `{"setting":"output","value":"json"}`)
+
+	if err := runLine(conf, commands, "map"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page := locationPageDoc{}
+	if err := json.Unmarshal(out.Bytes(), &page); err != nil {
+		t.Fatalf("expected a JSON document, got %q: %v", out.String(), err)
+	}
+	if len(page.Locations) != 2 || page.Locations[0].Name != "canalave-city-area" || page.Next == "" || page.Previous != "" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+	out.Reset()
+
+	if err := runLine(conf, commands, "explore canalave-city-area"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	explore := exploreDoc{}
+	if err := json.Unmarshal(out.Bytes(), &explore); err != nil {
+		t.Fatalf("expected a single JSON document, got %q: %v", out.String(), err)
+	}
+	if explore.Area != "canalave-city-area" || len(explore.Pokemon) != 2 || explore.Pokemon[1].Encounters[0].MaxLevel != 5 {
+		t.Errorf("unexpected explore: %+v", explore)
+	}
+	out.Reset()
+
+	pikachu, _ := conf.Client.GetPokemon("pikachu")
+	conf.Pokedex["pikachu"] = pikachu
+	if err := runLine(conf, commands, "inspect pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, `{"name":"pikachu","caught":true,"id":25,"height":4,"weight":60,"stats":{"attack":55,"defense":40,"hp":35,"special-attack":50,"special-defense":50,"speed":90},"types":["electric"]}`)
+
+	if err := runLine(conf, commands, "pokedex"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, `{"pokemon":[]}`)
+
+	conf.Owned.Add(collection.Owned{Species: "pikachu", PokemonID: 25, Level: 5, CaughtAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)})
+	if err := runLine(conf, commands, "pokedex"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, `{"pokemon":[{"id":1,"species":"pikachu","pokemon_id":25,"level":5,"experience":0,"caught_at":"2024-05-01T00:00:00Z"}]}`)
+
+	if err := runLine(conf, commands, "set output yaml"); err == nil {
+		t.Errorf("expected an error for an unknown output")
+	}
+}
+
+func TestCatchUsesBalls(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	conf.Bag = bag.Bag{Items: map[string]int{"master-ball": 1}}
+	conf.Location = "canalave-city-area"
+	conf.Wild = &encounter.Wild{Pokemon: "pikachu", Level: 4}
+
+	if err := runLine(conf, commands, "catch pikachu"); err == nil {
+		t.Errorf("expected an error without poke balls")
+	}
+	if err := runLine(conf, commands, "catch pikachu --ball master-ball"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Throwing a master-ball at pikachu (Lv. 4)...", "pikachu was caught!", "It was added to your Pokedex as #1.", "You earned ₽112.")
+	if conf.Wild != nil {
+		t.Errorf("expected the caught pokemon to be gone, got %+v", conf.Wild)
+	}
+	if conf.Bag.Count("master-ball") != 0 {
+		t.Errorf("expected the master ball to be used up")
+	}
+	if conf.Stats.Caught != 1 || conf.Stats.MoneyEarned != 112 {
+		t.Errorf("expected the catch to be counted, got %+v", conf.Stats)
+	}
+	if err := runLine(conf, commands, "catch --ball potion"); err == nil {
+		t.Errorf("expected an error throwing a potion")
+	}
+}
+
+func TestShopAndBuy(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	conf.Bag = bag.Bag{Money: 1000}
+
+	if err := runLine(conf, commands, "shop"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out,
+		"Welcome to the Poke Mart! 🛒",
+		" . poke-ball: ₽200",
+		" . great-ball: ₽600",
+		" . ultra-ball: ₽800",
+		" . potion: ₽200",
+		" . super-potion: ₽700",
+		" . hyper-potion: ₽1500",
+		"You have ₽1000",
+	)
+
+	if err := runLine(conf, commands, "buy poke-ball 3"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Bought 3 poke-ball for ₽600. You have ₽400 left.")
+	if err := runLine(conf, commands, "buy poke-ball 9223372036854775807"); !errors.Is(err, bag.ErrNotEnoughMoney) {
+		t.Errorf("expected ErrNotEnoughMoney for a quantity that overflows, got %v", err)
+	}
+	if conf.Bag.Money != 400 || conf.Bag.Count("poke-ball") != 3 {
+		t.Errorf("expected the bag to be unchanged, got %+v", conf.Bag)
+	}
+	if err := runLine(conf, commands, "buy great-ball"); !errors.Is(err, bag.ErrNotEnoughMoney) {
+		t.Errorf("expected ErrNotEnoughMoney, got %v", err)
+	}
+	if err := runLine(conf, commands, "buy master-ball"); err == nil {
+		t.Errorf("expected the master ball not to be for sale")
+	}
+	if err := runLine(conf, commands, "buy poke-ball zero"); err == nil {
+		t.Errorf("expected an error for a bad quantity")
+	}
+
+	if err := runLine(conf, commands, "bag"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Money: ₽400", "Your bag:", " . poke-ball x3")
+}
+
+func TestCommandEncounter(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	conf.Bag = bag.Bag{Items: map[string]int{"master-ball": 1}}
+
+	if err := commandEncounter(conf); !errors.Is(err, errNoLocation) {
+		t.Errorf("expected errNoLocation before exploring, got %v", err)
+	}
+	if err := commandCatch(conf); err == nil {
+		t.Errorf("expected an error with nothing to catch")
+	}
+
+	if err := commandExplore(conf, "eterna-city-area"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out.Reset()
+	if err := commandEncounter(conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conf.Wild == nil || conf.Wild.Pokemon != "psyduck" || conf.Wild.Level < 2 || conf.Wild.Level > 5 {
+		t.Fatalf("unexpected wild pokemon: %+v", conf.Wild)
+	}
+	level := conf.Wild.Level
+	expectLines(t, out, fmt.Sprintf("A wild psyduck (Lv. %v) appeared!", level))
+
+	if err := runLine(conf, getCommands(), "catch --ball master-ball"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, fmt.Sprintf("Throwing a master-ball at psyduck (Lv. %v)...", level), "psyduck was caught!", "It was added to your Pokedex as #1.", "You earned ₽64.")
+}
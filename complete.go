@@ -0,0 +1,66 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/lulock/pokedex/internal/lineedit"
+)
+
+// completes command names for the first word and hands the rest over to
+// the command's own complete func
+func completer(conf *config, commands map[string]cliCommand) lineedit.Completer {
+	return func(before string) []string {
+		fields := strings.Fields(strings.ToLower(before))
+		if len(fields) == 0 || (len(fields) == 1 && !strings.HasSuffix(before, " ")) {
+			names := make([]string, 0, len(commands))
+			for name := range commands {
+				names = append(names, name)
+			}
+			return names
+		}
+		cmd, ok := commands[fields[0]]
+		if !ok {
+			return nil
+		}
+		// the value of a --flag being typed
+		previous := fields[len(fields)-1]
+		if !strings.HasSuffix(before, " ") {
+			previous = fields[len(fields)-2]
+		}
+		if name, ok := strings.CutPrefix(previous, "--"); ok && cmd.flags[name] {
+			if complete := cmd.completeFlags[name]; complete != nil {
+				return complete(conf)
+			}
+			return nil
+		}
+		if cmd.complete == nil {
+			return nil
+		}
+		return cmd.complete(conf)
+	}
+}
+
+// location areas we've seen listed by map
+func completeAreas(conf *config) []string {
+	return keys(conf.SeenAreas)
+}
+
+// pokemon in the pokedex
+func completeCaughtPokemon(conf *config) []string {
+	names := make([]string, 0, len(conf.Pokedex))
+	for name := range conf.Pokedex {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func keys(set map[string]bool) []string {
+	result := make([]string, 0, len(set))
+	for k := range set {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
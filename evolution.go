@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lulock/pokedex/internal/evolution"
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+type chainNodeDoc struct {
+	Species string `json:"species"`
+	Caught  bool   `json:"caught"`
+	// the ways the previous stage evolves into this one, empty for the first
+	How       []string       `json:"how,omitempty"`
+	EvolvesTo []chainNodeDoc `json:"evolves_to"`
+}
+
+type evolutionChainDoc struct {
+	Pokemon string       `json:"pokemon"`
+	Chain   chainNodeDoc `json:"chain"`
+}
+
+func newChainNodeDoc(caught map[string]bool, link pokeapi.ChainLink) chainNodeDoc {
+	node := chainNodeDoc{Species: link.Species.Name, Caught: caught[link.Species.Name], EvolvesTo: []chainNodeDoc{}}
+	for _, detail := range link.EvolutionDetails {
+		node.How = append(node.How, evolution.Describe(detail))
+	}
+	for _, next := range link.EvolvesTo {
+		node.EvolvesTo = append(node.EvolvesTo, newChainNodeDoc(caught, next))
+	}
+	return node
+}
+
+// the species in the pokedex. it's keyed by pokemon name, which for forms
+// like wormadam-plant isn't the name of the species the chain uses.
+func caughtSpecies(conf *config) map[string]bool {
+	caught := make(map[string]bool)
+	for name, pokemon := range conf.Pokedex {
+		caught[name] = true
+		if pokemon.Species.Name != "" {
+			caught[pokemon.Species.Name] = true
+		}
+	}
+	return caught
+}
+
+// evolution command shows the whole evolution tree a pokemon belongs to,
+// how each stage is reached and which ones are in the pokedex
+func commandEvolution(conf *config, args ...string) error {
+	pokemon, err := conf.Client.GetPokemon(args[0])
+	if err != nil {
+		return err
+	}
+	species, err := conf.Client.GetPokemonSpecies(pokemon.Species.Name)
+	if err != nil {
+		return err
+	}
+
+	doc := evolutionChainDoc{Pokemon: pokemon.Name}
+	if species.EvolutionChain.URL == "" {
+		// a species on its own, like most legendaries
+		link := pokeapi.ChainLink{}
+		link.Species.Name = species.Name
+		doc.Chain = newChainNodeDoc(caughtSpecies(conf), link)
+	} else {
+		chain, err := conf.Client.GetEvolutionChain(species.EvolutionChain.URL)
+		if err != nil {
+			return err
+		}
+		doc.Chain = newChainNodeDoc(caughtSpecies(conf), chain.Chain)
+	}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("Evolution chain of %v (✓ caught):", doc.Pokemon))
+		fmt.Fprintln(conf.Out, chainNodeLabel(doc.Chain))
+		printChain(conf, doc.Chain.EvolvesTo, "")
+	})
+}
+
+// prints the stages as a tree, indent is what goes in front of each branch
+func printChain(conf *config, nodes []chainNodeDoc, indent string) {
+	for i, node := range nodes {
+		branch, next := "├─ ", "│  "
+		if i == len(nodes)-1 {
+			branch, next = "└─ ", "   "
+		}
+		label := chainNodeLabel(node)
+		if len(node.How) > 0 {
+			label += fmt.Sprintf(" (%v)", strings.Join(node.How, " or "))
+		}
+		fmt.Fprintln(conf.Out, indent+branch+label)
+		printChain(conf, node.EvolvesTo, indent+next)
+	}
+}
+
+func chainNodeLabel(node chainNodeDoc) string {
+	if node.Caught {
+		return node.Species + " ✓"
+	}
+	return node.Species
+}
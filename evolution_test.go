@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+func TestCommandEvolution(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	conf.Pokedex["pikachu"] = pokeapi.Pokemon{Name: "pikachu"}
+
+	// the whole chain shows up whichever stage is asked for
+	if err := runLine(conf, commands, "evolution raichu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out,
+		"Evolution chain of raichu (✓ caught):",
+		"pichu",
+		"└─ pikachu ✓ (level up with happiness 220+)",
+		"   └─ raichu (use thunder-stone)",
+	)
+
+	if err := runLine(conf, commands, "evolution eevee"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out,
+		"Evolution chain of eevee (✓ caught):",
+		"eevee",
+		"├─ vaporeon (use water-stone)",
+		"├─ jolteon (use thunder-stone)",
+		"├─ flareon (use fire-stone)",
+		"├─ espeon (level up with happiness 160+ during the day)",
+		"├─ umbreon (level up with happiness 160+ during the night)",
+		"├─ leafeon (use leaf-stone)",
+		"├─ glaceon (use ice-stone)",
+		"└─ sylveon (level up knowing a fairy move or level up with happiness 160+ knowing a fairy move)",
+	)
+
+	// tentacool doesn't evolve in the fake api
+	if err := runLine(conf, commands, "evolution tentacool"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Evolution chain of tentacool (✓ caught):", "tentacool")
+
+	conf.Output = outputJSON
+	if err := runLine(conf, commands, "evolution psyduck"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, `{"pokemon":"psyduck","chain":{"species":"psyduck","caught":false,"evolves_to":[{"species":"golduck","caught":false,"how":["Lv. 33"],"evolves_to":[]}]}}`)
+
+	// forms count as caught for their species
+	wormadam, err := conf.Client.GetPokemon("wormadam-plant")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conf.Pokedex[wormadam.Name] = wormadam
+	conf.Output = outputText
+	if err := runLine(conf, commands, "evolution burmy"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Evolution chain of burmy (✓ caught):", "burmy", "└─ wormadam ✓ (Lv. 20)")
+}
@@ -0,0 +1,137 @@
+// Package bag is the trainer's inventory: the items they carry and the
+// money they have to buy more with.
+package bag
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Kind groups items by what they're used for
+type Kind int
+
+const (
+	Ball Kind = iota
+	Potion
+)
+
+// Item is what the game knows about an item beyond what the API has
+type Item struct {
+	Name      string
+	Kind      Kind
+	BallBonus float64 // catch multiplier, balls only
+	Heal      int     // hp restored, potions only
+}
+
+// the items the game knows how to use, in the order the shop lists them.
+// the ball bonuses are the classic ones, a master ball never fails.
+var items = []Item{
+	{Name: "poke-ball", Kind: Ball, BallBonus: 1},
+	{Name: "great-ball", Kind: Ball, BallBonus: 1.5},
+	{Name: "ultra-ball", Kind: Ball, BallBonus: 2},
+	{Name: "master-ball", Kind: Ball, BallBonus: 255},
+	{Name: "potion", Kind: Potion, Heal: 20},
+	{Name: "super-potion", Kind: Potion, Heal: 60},
+	{Name: "hyper-potion", Kind: Potion, Heal: 120},
+}
+
+// DefaultBall is what gets thrown when no ball is asked for
+const DefaultBall = "poke-ball"
+
+// StarterMoney is what a new trainer starts out with
+const StarterMoney = 1000
+
+var (
+	// ErrUnknownItem is returned for items the game doesn't know how to use
+	ErrUnknownItem = errors.New("unknown item")
+	// ErrOutOfItem is returned when taking an item the bag has none of
+	ErrOutOfItem = errors.New("none left")
+	// ErrNotEnoughMoney is returned when a purchase costs more than the trainer has
+	ErrNotEnoughMoney = errors.New("not enough money")
+)
+
+// Lookup returns the item called name
+func Lookup(name string) (Item, error) {
+	for _, item := range items {
+		if item.Name == name {
+			return item, nil
+		}
+	}
+	return Item{}, fmt.Errorf("%w: %v", ErrUnknownItem, name)
+}
+
+// Known returns the names of every item the game knows, in shop order
+func Known() []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+// Bag holds item counts and money
+type Bag struct {
+	Items map[string]int `json:"items"`
+	Money int            `json:"money"`
+}
+
+// New returns the bag every new trainer starts with
+func New() Bag {
+	return Bag{
+		Items: map[string]int{DefaultBall: 10, "potion": 2},
+		Money: StarterMoney,
+	}
+}
+
+// Count is how many of name the bag holds
+func (b *Bag) Count(name string) int {
+	return b.Items[name]
+}
+
+// Add puts n of name in the bag
+func (b *Bag) Add(name string, n int) {
+	if b.Items == nil {
+		b.Items = make(map[string]int)
+	}
+	b.Items[name] += n
+}
+
+// Take removes one of name from the bag
+func (b *Bag) Take(name string) error {
+	if b.Items[name] <= 0 {
+		return fmt.Errorf("%w: you're out of %v", ErrOutOfItem, name)
+	}
+	b.Items[name]--
+	if b.Items[name] == 0 {
+		delete(b.Items, name)
+	}
+	return nil
+}
+
+// Buy pays for quantity of name at unitCost each and adds them to the bag
+func (b *Bag) Buy(name string, quantity, unitCost int) error {
+	if quantity < 1 || unitCost < 0 {
+		return fmt.Errorf("can't buy %v %v at ₽%v each", quantity, name, unitCost)
+	}
+	// compare before multiplying, a huge quantity would wrap the total around
+	if unitCost > 0 && quantity > b.Money/unitCost {
+		return fmt.Errorf("%w: %v %v at %v each is more than the %v you have", ErrNotEnoughMoney, quantity, name, unitCost, b.Money)
+	}
+	total := quantity * unitCost
+	b.Money -= total
+	b.Add(name, quantity)
+	return nil
+}
+
+// Names returns the items in the bag, sorted
+func (b *Bag) Names() []string {
+	names := make([]string, 0, len(b.Items))
+	for name, n := range b.Items {
+		if n > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,50 @@
+package bag
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestTakeAndBuy(t *testing.T) {
+	b := Bag{Money: 500}
+	if err := b.Take(DefaultBall); !errors.Is(err, ErrOutOfItem) {
+		t.Errorf("expected ErrOutOfItem from an empty bag, got %v", err)
+	}
+
+	if err := b.Buy("great-ball", 2, 200); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Money != 100 || b.Count("great-ball") != 2 {
+		t.Errorf("unexpected bag after buying: %+v", b)
+	}
+	if err := b.Buy("great-ball", 1, 200); !errors.Is(err, ErrNotEnoughMoney) {
+		t.Errorf("expected ErrNotEnoughMoney, got %v", err)
+	}
+	if b.Money != 100 || b.Count("great-ball") != 2 {
+		t.Errorf("expected a failed purchase to change nothing, got %+v", b)
+	}
+	// a quantity big enough to overflow the total mustn't make money
+	if err := b.Buy("poke-ball", math.MaxInt, 200); !errors.Is(err, ErrNotEnoughMoney) {
+		t.Errorf("expected ErrNotEnoughMoney, got %v", err)
+	}
+	if b.Money != 100 || b.Count("poke-ball") != 0 {
+		t.Errorf("expected an overflowing purchase to change nothing, got %+v", b)
+	}
+
+	b.Take("great-ball")
+	b.Take("great-ball")
+	if names := b.Names(); len(names) != 0 {
+		t.Errorf("expected the bag to be empty, got %v", names)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	ball, err := Lookup("ultra-ball")
+	if err != nil || ball.Kind != Ball || ball.BallBonus != 2 {
+		t.Errorf("unexpected ultra-ball: %+v %v", ball, err)
+	}
+	if _, err := Lookup("rare-candy"); !errors.Is(err, ErrUnknownItem) {
+		t.Errorf("expected ErrUnknownItem, got %v", err)
+	}
+}
@@ -0,0 +1,302 @@
+// Package battle is a turn based, single pokemon battle engine.
+// it doesn't fetch anything or print anything, all randomness comes from the
+// *rand.Rand it's given so a seeded battle always plays out the same way.
+package battle
+
+import (
+	"math/rand"
+	"slices"
+
+	"github.com/lulock/pokedex/internal/typechart"
+)
+
+// damage classes of a move
+const (
+	Physical = "physical"
+	Special  = "special"
+	Status   = "status"
+)
+
+// CriticalOdds is the 1 in CriticalOdds chance of a critical hit
+const CriticalOdds = 24
+
+// Move is what a combatant can do on its turn
+type Move struct {
+	Name  string
+	Type  string
+	Class string // Physical, Special or Status
+	// Power 0 means the move doesn't deal damage the usual way. status moves
+	// and fixed damage moves aren't modelled, they just do nothing.
+	Power int
+	// Accuracy in percent, 0 means the move never misses
+	Accuracy int
+	PP       int
+	Priority int
+}
+
+// Struggle is used when a combatant has no PP left in any move.
+// it has no type and hurts the user a quarter of its max HP.
+var Struggle = Move{Name: "struggle", Class: Physical, Power: 50}
+
+// Stats are either base stats or the actual stats at a level
+type Stats struct {
+	HP             int
+	Attack         int
+	Defense        int
+	SpecialAttack  int
+	SpecialDefense int
+	Speed          int
+}
+
+// StatsAt works out the stats of a pokemon with the given base stats at
+// level, with the Gen III formula and no IVs, EVs or nature
+func StatsAt(base Stats, level int) Stats {
+	stat := func(b int) int {
+		return 2*b*level/100 + 5
+	}
+	return Stats{
+		HP:             2*base.HP*level/100 + level + 10,
+		Attack:         stat(base.Attack),
+		Defense:        stat(base.Defense),
+		SpecialAttack:  stat(base.SpecialAttack),
+		SpecialDefense: stat(base.SpecialDefense),
+		Speed:          stat(base.Speed),
+	}
+}
+
+// Combatant is one pokemon in a battle
+type Combatant struct {
+	Name  string
+	Level int
+	Types []string
+	Stats Stats // actual stats, Stats.HP is the max HP
+	HP    int
+	Moves []Move
+	PP    []int // PP left for each move
+}
+
+// NewCombatant builds a combatant at full health with full PP
+func NewCombatant(name string, level int, types []string, base Stats, moves []Move) *Combatant {
+	c := &Combatant{
+		Name:  name,
+		Level: level,
+		Types: types,
+		Stats: StatsAt(base, level),
+		Moves: moves,
+		PP:    make([]int, len(moves)),
+	}
+	c.HP = c.Stats.HP
+	for i, move := range moves {
+		c.PP[i] = move.PP
+	}
+	return c
+}
+
+// Fainted reports whether c is out of HP
+func (c *Combatant) Fainted() bool {
+	return c.HP <= 0
+}
+
+// Heal restores up to hp HP without going over the max and returns how
+// much was restored. fainted combatants can't be healed.
+func (c *Combatant) Heal(hp int) int {
+	if c.Fainted() {
+		return 0
+	}
+	healed := min(hp, c.Stats.HP-c.HP)
+	c.HP += healed
+	return healed
+}
+
+// MoveIndex returns the index of the named move, -1 when c doesn't know it
+func (c *Combatant) MoveIndex(name string) int {
+	return slices.IndexFunc(c.Moves, func(m Move) bool {
+		return m.Name == name
+	})
+}
+
+// usable reports whether move i can be used
+func (c *Combatant) usable(i int) bool {
+	return i >= 0 && i < len(c.Moves) && c.PP[i] > 0
+}
+
+// Damage works out how much damage move does, before the random roll, crits
+// and the minimum of 1. effectiveness is returned along with it.
+func Damage(attacker, defender *Combatant, move Move, chart *typechart.Chart) (float64, float64) {
+	effectiveness := chart.Effectiveness(move.Type, defender.Types)
+	if move.Power == 0 || move.Class == Status {
+		return 0, effectiveness
+	}
+	attack, defense := attacker.Stats.Attack, defender.Stats.Defense
+	if move.Class == Special {
+		attack, defense = attacker.Stats.SpecialAttack, defender.Stats.SpecialDefense
+	}
+	damage := float64((2*attacker.Level/5+2)*move.Power*attack/max(defense, 1)/50 + 2)
+	if move.Type != "" && slices.Contains(attacker.Types, move.Type) {
+		// same type attack bonus
+		damage *= 1.5
+	}
+	return damage * effectiveness, effectiveness
+}
+
+// Event is something that happened during a turn
+type Event struct {
+	Side          int // who acted, 0 or 1
+	Attacker      string
+	Defender      string
+	Move          string
+	Missed        bool
+	NoEffect      bool // status moves, and types that are immune
+	Critical      bool
+	Effectiveness float64
+	Damage        int
+	Recoil        int
+	Fainted       bool // the defender fainted
+	AttackerDown  bool // the attacker fainted from recoil
+}
+
+// Battle is two combatants taking turns until one faints
+type Battle struct {
+	Sides [2]*Combatant
+	Chart *typechart.Chart
+	Turns int
+	// Winner is the side that won, -1 while the battle goes on
+	Winner int
+
+	rng *rand.Rand
+}
+
+// New starts a battle between side 0 and side 1
+func New(side0, side1 *Combatant, chart *typechart.Chart, rng *rand.Rand) *Battle {
+	return &Battle{
+		Sides:  [2]*Combatant{side0, side1},
+		Chart:  chart,
+		Winner: -1,
+		rng:    rng,
+	}
+}
+
+// Over reports whether one side has fainted
+func (b *Battle) Over() bool {
+	return b.Winner >= 0
+}
+
+// Turn plays one turn, each side uses the move at the given index.
+// an index without PP left, or -1, struggles. the side with the higher
+// priority move goes first, then the faster one, ties are random.
+func (b *Battle) Turn(move0, move1 int) []Event {
+	moves := [2]int{move0, move1}
+	first := 0
+	p0, p1 := b.priority(0, move0), b.priority(1, move1)
+	s0, s1 := b.Sides[0].Stats.Speed, b.Sides[1].Stats.Speed
+	switch {
+	case p0 != p1:
+		if p1 > p0 {
+			first = 1
+		}
+	case s0 != s1:
+		if s1 > s0 {
+			first = 1
+		}
+	default:
+		first = b.rng.Intn(2)
+	}
+
+	b.Turns++
+	events := []Event{}
+	for _, side := range []int{first, 1 - first} {
+		if b.Over() {
+			break
+		}
+		events = append(events, b.Act(side, moves[side]))
+	}
+	return events
+}
+
+func (b *Battle) priority(side, move int) int {
+	if !b.Sides[side].usable(move) {
+		return Struggle.Priority
+	}
+	return b.Sides[side].Moves[move].Priority
+}
+
+// Act has one side use a move without the other side moving back, like when
+// the trainer throws a ball instead of attacking
+func (b *Battle) Act(side, moveIndex int) Event {
+	attacker, defender := b.Sides[side], b.Sides[1-side]
+	move := Struggle
+	if attacker.usable(moveIndex) {
+		move = attacker.Moves[moveIndex]
+		attacker.PP[moveIndex]--
+	}
+	event := Event{Side: side, Attacker: attacker.Name, Defender: defender.Name, Move: move.Name, Effectiveness: 1}
+
+	if move.Accuracy > 0 && b.rng.Intn(100) >= move.Accuracy {
+		event.Missed = true
+		return event
+	}
+	damage, effectiveness := Damage(attacker, defender, move, b.Chart)
+	event.Effectiveness = effectiveness
+	if damage == 0 {
+		event.NoEffect = true
+		return event
+	}
+	if b.rng.Intn(CriticalOdds) == 0 {
+		event.Critical = true
+		damage *= 1.5
+	}
+	// the random roll, 85% to 100%
+	damage = damage * float64(85+b.rng.Intn(16)) / 100
+	event.Damage = min(max(int(damage), 1), defender.HP)
+	defender.HP -= event.Damage
+	if move.Name == Struggle.Name {
+		event.Recoil = min(max(attacker.Stats.HP/4, 1), attacker.HP)
+		attacker.HP -= event.Recoil
+	}
+
+	event.Fainted = defender.Fainted()
+	event.AttackerDown = attacker.Fainted()
+	switch {
+	case event.Fainted:
+		b.Winner = side
+	case event.AttackerDown:
+		b.Winner = 1 - side
+	}
+	return event
+}
+
+// BestMove is the trainer AI: the move with the most expected damage
+// against the other side, -1 when nothing has PP left
+func (b *Battle) BestMove(side int) int {
+	attacker, defender := b.Sides[side], b.Sides[1-side]
+	best, bestScore := -1, -1.0
+	for i, move := range attacker.Moves {
+		if !attacker.usable(i) {
+			continue
+		}
+		damage, _ := Damage(attacker, defender, move, b.Chart)
+		score := damage
+		if move.Accuracy > 0 {
+			score *= float64(move.Accuracy) / 100
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// RandomMove is how wild pokemon fight: any move with PP left, -1 when
+// nothing has PP left
+func (b *Battle) RandomMove(side int) int {
+	usable := []int{}
+	for i := range b.Sides[side].Moves {
+		if b.Sides[side].usable(i) {
+			usable = append(usable, i)
+		}
+	}
+	if len(usable) == 0 {
+		return -1
+	}
+	return usable[b.rng.Intn(len(usable))]
+}
@@ -0,0 +1,161 @@
+package battle
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/lulock/pokedex/internal/typechart"
+)
+
+var chart = func() *typechart.Chart {
+	c := typechart.New()
+	for attacking, multipliers := range map[string]map[string]float64{
+		"electric": {"water": 2, "flying": 2, "electric": 0.5, "grass": 0.5, "dragon": 0.5, "ground": 0},
+		"water":    {"fire": 2, "ground": 2, "rock": 2, "water": 0.5, "grass": 0.5, "dragon": 0.5},
+		"normal":   {"rock": 0.5, "steel": 0.5, "ghost": 0},
+	} {
+		for defending, m := range multipliers {
+			c.Set(attacking, defending, m)
+		}
+	}
+	return c
+}()
+
+var (
+	thunderShock = Move{Name: "thunder-shock", Type: "electric", Class: Special, Power: 40, Accuracy: 100, PP: 30}
+	quickAttack  = Move{Name: "quick-attack", Type: "normal", Class: Physical, Power: 40, Accuracy: 100, PP: 30, Priority: 1}
+	growl        = Move{Name: "growl", Type: "normal", Class: Status, Accuracy: 100, PP: 40}
+	scratch      = Move{Name: "scratch", Type: "normal", Class: Physical, Power: 40, Accuracy: 100, PP: 35}
+	waterGun     = Move{Name: "water-gun", Type: "water", Class: Special, Power: 40, Accuracy: 100, PP: 25}
+)
+
+func pikachu(moves ...Move) *Combatant {
+	return NewCombatant("pikachu", 10, []string{"electric"}, Stats{35, 55, 40, 50, 50, 90}, moves)
+}
+
+func psyduck(moves ...Move) *Combatant {
+	return NewCombatant("psyduck", 10, []string{"water"}, Stats{50, 52, 48, 65, 50, 55}, moves)
+}
+
+func TestStatsAt(t *testing.T) {
+	actual := StatsAt(Stats{35, 55, 40, 50, 50, 90}, 50)
+	expected := Stats{HP: 95, Attack: 60, Defense: 45, SpecialAttack: 55, SpecialDefense: 55, Speed: 95}
+	if actual != expected {
+		t.Errorf("Expected: %+v, but got %+v.", expected, actual)
+	}
+}
+
+func TestDamage(t *testing.T) {
+	attacker, defender := pikachu(), psyduck()
+
+	// (2*10/5+2) * 40 * 25/25 / 50 + 2 = 6, then STAB and super effective
+	damage, effectiveness := Damage(attacker, defender, thunderShock, chart)
+	if damage != 18 || effectiveness != 2 {
+		t.Errorf("expected 18 damage at 2x, got %v at %vx", damage, effectiveness)
+	}
+	// no STAB, neutral, attack 16 against defense 14
+	if damage, _ := Damage(attacker, defender, scratch, chart); damage != 7 {
+		t.Errorf("Expected: %v, but got %v.", 7, damage)
+	}
+	if damage, _ := Damage(attacker, defender, growl, chart); damage != 0 {
+		t.Errorf("expected status moves to do no damage, got %v", damage)
+	}
+}
+
+func TestTurnOrder(t *testing.T) {
+	// pikachu is faster
+	b := New(pikachu(thunderShock), psyduck(scratch), chart, rand.New(rand.NewSource(1)))
+	if events := b.Turn(0, 0); events[0].Attacker != "pikachu" {
+		t.Errorf("expected the faster pokemon to go first, got %+v", events)
+	}
+
+	// priority beats speed
+	b = New(pikachu(thunderShock), psyduck(quickAttack), chart, rand.New(rand.NewSource(1)))
+	if events := b.Turn(0, 0); events[0].Attacker != "psyduck" {
+		t.Errorf("expected the priority move to go first, got %+v", events)
+	}
+}
+
+func TestBattleIsSeedable(t *testing.T) {
+	play := func(seed int64) []Event {
+		b := New(pikachu(thunderShock, quickAttack), psyduck(scratch, waterGun), chart, rand.New(rand.NewSource(seed)))
+		events := []Event{}
+		for !b.Over() {
+			events = append(events, b.Turn(b.BestMove(0), b.RandomMove(1))...)
+		}
+		return events
+	}
+
+	first, second := play(7), play(7)
+	if len(first) != len(second) {
+		t.Fatalf("expected the same battle twice, got %v and %v events", len(first), len(second))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("event %v: expected %+v, got %+v", i, first[i], second[i])
+		}
+	}
+	if last := first[len(first)-1]; !last.Fainted || last.Defender != "psyduck" {
+		t.Errorf("expected pikachu to win with super effective moves, got %+v", last)
+	}
+}
+
+func TestPPAndStruggle(t *testing.T) {
+	short := thunderShock
+	short.PP = 1
+	b := New(pikachu(short), psyduck(growl), chart, rand.New(rand.NewSource(1)))
+
+	b.Turn(0, 0)
+	if b.Sides[0].PP[0] != 0 {
+		t.Errorf("expected the move to use up its PP, got %v", b.Sides[0].PP)
+	}
+	if best := b.BestMove(0); best != -1 {
+		t.Errorf("expected no usable moves, got %v", best)
+	}
+
+	hp := b.Sides[0].HP
+	event := b.Act(0, 0)
+	if event.Move != "struggle" || event.Recoil == 0 || b.Sides[0].HP != hp-event.Recoil {
+		t.Errorf("expected pikachu to struggle and take recoil, got %+v", event)
+	}
+}
+
+func TestHeal(t *testing.T) {
+	c := pikachu(thunderShock)
+	c.HP -= 10
+	if healed := c.Heal(20); healed != 10 || c.HP != c.Stats.HP {
+		t.Errorf("expected to heal up to the max HP, healed %v to %v/%v", healed, c.HP, c.Stats.HP)
+	}
+	c.HP = 0
+	if healed := c.Heal(20); healed != 0 || c.HP != 0 {
+		t.Errorf("expected a fainted pokemon not to heal, healed %v", healed)
+	}
+}
+
+func TestStatusAndImmunity(t *testing.T) {
+	b := New(pikachu(thunderShock), psyduck(growl), chart, rand.New(rand.NewSource(1)))
+	if event := b.Act(1, 0); !event.NoEffect || event.Damage != 0 {
+		t.Errorf("expected growl to do nothing, got %+v", event)
+	}
+
+	ground := NewCombatant("diglett", 10, []string{"ground"}, Stats{10, 55, 25, 35, 45, 95}, nil)
+	b = New(pikachu(thunderShock), ground, chart, rand.New(rand.NewSource(1)))
+	if event := b.Act(0, 0); !event.NoEffect || event.Effectiveness != 0 {
+		t.Errorf("expected ground types to be immune to electric moves, got %+v", event)
+	}
+}
+
+func TestAccuracy(t *testing.T) {
+	missy := scratch
+	missy.Accuracy = 1
+	b := New(pikachu(missy), psyduck(), chart, rand.New(rand.NewSource(1)))
+	misses := 0
+	for i := 0; i < 20; i++ {
+		if b.Act(0, 0).Missed {
+			misses++
+		}
+	}
+	if misses < 15 {
+		t.Errorf("expected a 1%% accurate move to mostly miss, it missed %v times", misses)
+	}
+}
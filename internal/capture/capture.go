@@ -0,0 +1,95 @@
+// Package capture is the Gen III+ catch formula: the species capture rate,
+// how hurt the pokemon is, the ball and any status condition make up a catch
+// value, which decides the odds of each of the four ball shakes.
+package capture
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Status is the non-volatile status condition of the wild pokemon
+type Status int
+
+const (
+	None Status = iota
+	Sleep
+	Freeze
+	Paralysis
+	Poison
+	Burn
+)
+
+// Modifier is how much the status helps a catch
+func (s Status) Modifier() float64 {
+	switch s {
+	case Sleep, Freeze:
+		return 2
+	case Paralysis, Poison, Burn:
+		return 1.5
+	}
+	return 1
+}
+
+// Attempt is everything that goes into a throw
+type Attempt struct {
+	CaptureRate int     // from the species, 3 for legendaries up to 255 for the likes of magikarp
+	MaxHP       int     // of the wild pokemon
+	CurrentHP   int     // of the wild pokemon, full health when 0
+	Ball        float64 // ball bonus, 1 for a plain poke ball
+	Status      Status
+}
+
+// Shakes is how many times the ball wobbles before the pokemon is caught
+const Shakes = 4
+
+// Value is the modified catch rate "a". 255 or more is a guaranteed catch.
+func Value(a Attempt) float64 {
+	maxHP := float64(max(a.MaxHP, 1))
+	currentHP := maxHP
+	if a.CurrentHP > 0 {
+		currentHP = math.Min(float64(a.CurrentHP), maxHP)
+	}
+	ball := a.Ball
+	if ball == 0 {
+		ball = 1
+	}
+	return (3*maxHP - 2*currentHP) * float64(a.CaptureRate) * ball / (3 * maxHP) * a.Status.Modifier()
+}
+
+// shakeThreshold is "b", each shake check passes when a random number in
+// [0, 65536) is below it
+func shakeThreshold(value float64) float64 {
+	return 65536 / math.Pow(255/value, 0.25)
+}
+
+// Chance is the probability of the attempt succeeding, between 0 and 1
+func Chance(a Attempt) float64 {
+	value := Value(a)
+	if value >= 255 {
+		return 1
+	}
+	if value <= 0 {
+		return 0
+	}
+	return math.Pow(shakeThreshold(value)/65536, Shakes)
+}
+
+// Throw rolls the shake checks with rng. it returns how many shakes passed
+// and whether the pokemon was caught, which is every shake passing.
+func Throw(a Attempt, rng *rand.Rand) (shakes int, caught bool) {
+	value := Value(a)
+	if value >= 255 {
+		return Shakes, true
+	}
+	if value <= 0 {
+		return 0, false
+	}
+	threshold := shakeThreshold(value)
+	for shakes = 0; shakes < Shakes; shakes++ {
+		if float64(rng.Intn(65536)) >= threshold {
+			return shakes, false
+		}
+	}
+	return Shakes, true
+}
@@ -0,0 +1,66 @@
+package capture
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestChance(t *testing.T) {
+	cases := []struct {
+		name     string
+		attempt  Attempt
+		expected float64
+	}{
+		// at full health a is a third of the capture rate, and the chance
+		// works out to roughly a/255
+		{name: "pidgey", attempt: Attempt{CaptureRate: 255, MaxHP: 40}, expected: 1.0 / 3},
+		{name: "mewtwo", attempt: Attempt{CaptureRate: 3, MaxHP: 106}, expected: 1.0 / 255},
+		{name: "one hp", attempt: Attempt{CaptureRate: 45, MaxHP: 100, CurrentHP: 1}, expected: 0.176},
+		{name: "asleep", attempt: Attempt{CaptureRate: 45, MaxHP: 100, Status: Sleep}, expected: 30.0 / 255},
+		{name: "master ball", attempt: Attempt{CaptureRate: 3, MaxHP: 106, Ball: 255}, expected: 1},
+	}
+	for _, c := range cases {
+		actual := Chance(c.attempt)
+		if math.Abs(actual-c.expected) > 0.005 {
+			t.Errorf("%v: expected chance %.3f, got %.3f", c.name, c.expected, actual)
+		}
+	}
+
+	if Chance(Attempt{CaptureRate: 3, MaxHP: 106}) >= Chance(Attempt{CaptureRate: 255, MaxHP: 40}) {
+		t.Errorf("expected mewtwo to be harder to catch than pidgey")
+	}
+}
+
+func TestThrowMatchesChance(t *testing.T) {
+	attempt := Attempt{CaptureRate: 45, MaxHP: 100, CurrentHP: 50}
+	rng := rand.New(rand.NewSource(1))
+	const throws = 20000
+	caught := 0
+	for i := 0; i < throws; i++ {
+		shakes, ok := Throw(attempt, rng)
+		if ok != (shakes == Shakes) {
+			t.Fatalf("caught should mean every shake passed, got %v shakes and %v", shakes, ok)
+		}
+		if ok {
+			caught++
+		}
+	}
+	rate := float64(caught) / throws
+	if expected := Chance(attempt); math.Abs(rate-expected) > 0.02 {
+		t.Errorf("expected a catch rate near %.3f, got %.3f", expected, rate)
+	}
+}
+
+func TestThrowIsSeedable(t *testing.T) {
+	attempt := Attempt{CaptureRate: 45, MaxHP: 100}
+	a := rand.New(rand.NewSource(42))
+	b := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		shakesA, caughtA := Throw(attempt, a)
+		shakesB, caughtB := Throw(attempt, b)
+		if shakesA != shakesB || caughtA != caughtB {
+			t.Fatalf("expected the same rolls from the same seed")
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Package collection keeps track of the individual pokemon a trainer owns.
+// the species data (stats, types...) lives in the pokedex, an Owned pokemon
+// only records what makes this one pokemon different from the rest.
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxNickname is the longest nickname a pokemon can have, in characters
+const MaxNickname = 12
+
+// MaxMoves is how many moves a pokemon can know at once
+const MaxMoves = 4
+
+// ShinyOdds is the 1 in ShinyOdds chance a caught pokemon is shiny
+const ShinyOdds = 4096
+
+// ErrNotOwned is returned when looking up a pokemon the trainer doesn't have
+var ErrNotOwned = errors.New("you don't have that pokemon")
+
+// Owned is one caught pokemon
+type Owned struct {
+	ID        int    `json:"id"` // unique for the trainer, never reused
+	Species   string `json:"species"`
+	PokemonID int    `json:"pokemon_id"` // national dex number
+	Nickname  string `json:"nickname,omitempty"`
+	Level     int    `json:"level"`
+	// Experience is the total earned, not the amount since the last level
+	Experience int       `json:"experience"`
+	Moves      []string  `json:"moves,omitempty"` // at most MaxMoves, oldest first
+	CaughtAt   time.Time `json:"caught_at"`
+	CaughtIn   string    `json:"caught_in,omitempty"` // location area
+	Shiny      bool      `json:"shiny,omitempty"`
+}
+
+// Name is the nickname when there is one, the species otherwise
+func (o Owned) Name() string {
+	if o.Nickname != "" {
+		return o.Nickname
+	}
+	return o.Species
+}
+
+// SetNickname renames the pokemon. an empty name, or the species name,
+// clears the nickname.
+func (o *Owned) SetNickname(name string) error {
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > MaxNickname {
+		return fmt.Errorf("nicknames can be at most %v characters long", MaxNickname)
+	}
+	if name == o.Species {
+		name = ""
+	}
+	o.Nickname = name
+	return nil
+}
+
+// Learn teaches the pokemon move. a pokemon that already knows MaxMoves
+// forgets its oldest move to make room, which is returned as forgotten.
+// learned is false when it already knew the move.
+func (o *Owned) Learn(move string) (forgotten string, learned bool) {
+	if slices.Contains(o.Moves, move) {
+		return "", false
+	}
+	if len(o.Moves) >= MaxMoves {
+		forgotten = o.Moves[0]
+		o.Moves = o.Moves[1:]
+	}
+	o.Moves = append(o.Moves, move)
+	return forgotten, true
+}
+
+// String is how the pokemon shows up in listings, e.g. "#3 Sparky (pikachu) Lv. 5"
+func (o Owned) String() string {
+	name := o.Species
+	if o.Nickname != "" {
+		name = fmt.Sprintf("%v (%v)", o.Nickname, o.Species)
+	}
+	if o.Shiny {
+		name += " ✨"
+	}
+	return fmt.Sprintf("#%v %v Lv. %v", o.ID, name, o.Level)
+}
+
+// Collection is every pokemon a trainer owns, in the order they were caught
+type Collection struct {
+	NextID  int     `json:"next_id"`
+	Pokemon []Owned `json:"pokemon"`
+	// Party is the ids of the pokemon the trainer carries, the lead first
+	Party []int `json:"party"`
+	// Boxes is the ids in each pc box, box 1 first
+	Boxes [][]int `json:"boxes"`
+	// Imported is the checksums of the transfer files pokemon arrived in,
+	// so the same file can't bring a pokemon in twice
+	Imported []string `json:"imported,omitempty"`
+}
+
+// Add gives o the next free ID and stores it in the party, or in a pc box
+// when the party is full. box is where it went, 0 for the party.
+func (c *Collection) Add(o Owned) (added Owned, box int) {
+	if c.NextID < 1 {
+		c.NextID = 1
+	}
+	o.ID = c.NextID
+	c.NextID++
+	c.Pokemon = append(c.Pokemon, o)
+	return o, c.store(o.ID)
+}
+
+// Get returns the pokemon with the given ID
+func (c *Collection) Get(id int) (*Owned, error) {
+	for i := range c.Pokemon {
+		if c.Pokemon[i].ID == id {
+			return &c.Pokemon[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: #%v", ErrNotOwned, id)
+}
+
+// Lookup resolves what a user typed to a pokemon: an ID like 3 or #3
+func (c *Collection) Lookup(ref string) (*Owned, error) {
+	id, err := ParseID(ref)
+	if err != nil {
+		return nil, err
+	}
+	return c.Get(id)
+}
+
+// ParseID reads an ID like 3 or #3
+func ParseID(ref string) (int, error) {
+	id, err := strconv.Atoi(ref)
+	if len(ref) > 1 && ref[0] == '#' {
+		id, err = strconv.Atoi(ref[1:])
+	}
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("%q is not a pokemon id, ids look like 3 or #3", ref)
+	}
+	return id, nil
+}
+
+// OfSpecies returns every owned pokemon of species
+func (c *Collection) OfSpecies(species string) []Owned {
+	result := []Owned{}
+	for _, o := range c.Pokemon {
+		if o.Species == species {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+// Releasable reports why the pokemon with the given ID can't be let go,
+// nil when it can. like Deposit, a release won't leave the party empty.
+func (c *Collection) Releasable(id int) error {
+	if _, err := c.Get(id); err != nil {
+		return err
+	}
+	if c.lastInParty(id) {
+		return ErrLastInParty
+	}
+	return nil
+}
+
+// Release removes the pokemon with the given ID and returns it
+func (c *Collection) Release(id int) (Owned, error) {
+	if err := c.Releasable(id); err != nil {
+		return Owned{}, err
+	}
+	for i, o := range c.Pokemon {
+		if o.ID == id {
+			c.Pokemon = append(c.Pokemon[:i], c.Pokemon[i+1:]...)
+			c.unstore(id)
+			return o, nil
+		}
+	}
+	return Owned{}, fmt.Errorf("%w: #%v", ErrNotOwned, id)
+}
+
+// IDs returns every ID in the collection as strings, for completion
+func (c *Collection) IDs() []string {
+	ids := make([]string, 0, len(c.Pokemon))
+	for _, o := range c.Pokemon {
+		ids = append(ids, strconv.Itoa(o.ID))
+	}
+	return ids
+}
@@ -0,0 +1,97 @@
+package collection
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestAddReleaseKeepsIDsUnique(t *testing.T) {
+	c := Collection{}
+	first, _ := c.Add(Owned{Species: "pikachu", Level: 5})
+	second, _ := c.Add(Owned{Species: "pikachu", Level: 7})
+	if first.ID != 1 || second.ID != 2 {
+		t.Fatalf("expected ids 1 and 2, got %v and %v", first.ID, second.ID)
+	}
+	if n := len(c.OfSpecies("pikachu")); n != 2 {
+		t.Errorf("expected two pikachu, got %v", n)
+	}
+
+	if _, err := c.Release(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	third, _ := c.Add(Owned{Species: "psyduck"})
+	if third.ID != 3 {
+		t.Errorf("expected released ids not to be reused, got %v", third.ID)
+	}
+	if _, err := c.Release(1); !errors.Is(err, ErrNotOwned) {
+		t.Errorf("expected ErrNotOwned, got %v", err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	c := Collection{}
+	c.Add(Owned{Species: "pikachu"})
+
+	for _, ref := range []string{"1", "#1"} {
+		o, err := c.Lookup(ref)
+		if err != nil || o.Species != "pikachu" {
+			t.Errorf("%v: unexpected lookup %+v %v", ref, o, err)
+		}
+	}
+	if _, err := c.Lookup("2"); !errors.Is(err, ErrNotOwned) {
+		t.Errorf("expected ErrNotOwned, got %v", err)
+	}
+	if _, err := c.Lookup("pikachu"); err == nil {
+		t.Errorf("expected an error for something that isn't an id")
+	}
+
+	// lookups hand back the stored pokemon so it can be changed in place
+	o, _ := c.Lookup("1")
+	o.Nickname = "Sparky"
+	if c.Pokemon[0].Name() != "Sparky" || c.Pokemon[0].String() != "#1 Sparky (pikachu) Lv. 0" {
+		t.Errorf("unexpected pokemon after renaming: %v", c.Pokemon[0])
+	}
+}
+
+func TestSetNickname(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected string
+		err      bool
+	}{
+		{name: "Sparky", expected: "Sparky"},
+		{name: "  Sparky  ", expected: "Sparky"},
+		{name: "", expected: "pikachu"},
+		{name: "pikachu", expected: "pikachu"},
+		{name: "Thunderstruck!", expected: "pikachu", err: true},
+	}
+	for _, c := range cases {
+		o := Owned{Species: "pikachu"}
+		err := o.SetNickname(c.name)
+		if (err != nil) != c.err {
+			t.Errorf("SetNickname(%q): unexpected error %v", c.name, err)
+		}
+		if o.Name() != c.expected {
+			t.Errorf("SetNickname(%q): expected: %v, but got %v.", c.name, c.expected, o.Name())
+		}
+	}
+}
+
+func TestLearn(t *testing.T) {
+	o := Owned{Species: "pikachu", Moves: []string{"thunder-shock", "growl", "tail-whip"}}
+
+	if forgotten, learned := o.Learn("thunder-wave"); forgotten != "" || !learned {
+		t.Errorf("expected thunder-wave to fill the last slot, got %q %v", forgotten, learned)
+	}
+	if _, learned := o.Learn("growl"); learned {
+		t.Errorf("expected growl not to be learned twice")
+	}
+	if forgotten, learned := o.Learn("quick-attack"); forgotten != "thunder-shock" || !learned {
+		t.Errorf("expected the oldest move to be forgotten, got %q %v", forgotten, learned)
+	}
+	expected := []string{"growl", "tail-whip", "thunder-wave", "quick-attack"}
+	if !slices.Equal(o.Moves, expected) {
+		t.Errorf("Expected: %v, but got %v.", expected, o.Moves)
+	}
+}
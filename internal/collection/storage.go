@@ -0,0 +1,223 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// PartySize is how many pokemon a trainer carries around
+const PartySize = 6
+
+// BoxSize is how many pokemon fit in one pc box
+const BoxSize = 30
+
+// ErrPartyFull is returned when there's no room left in the party
+var ErrPartyFull = errors.New("your party is full")
+
+// ErrLastInParty is returned for anything that would leave the party empty
+var ErrLastInParty = errors.New("that's the last pokemon in your party, it has to stay")
+
+// the party and the boxes only hold ids, the pokemon themselves stay in
+// Collection.Pokemon. box 0 is the party, pc boxes are numbered from 1.
+
+// store puts id in the party, or the first box with room when the party
+// is full, and returns where it went
+func (c *Collection) store(id int) int {
+	if len(c.Party) < PartySize {
+		c.Party = append(c.Party, id)
+		return 0
+	}
+	box := c.freeBox()
+	c.Boxes[box-1] = append(c.Boxes[box-1], id)
+	return box
+}
+
+// freeBox is the first box with room, opening a new one when they're all full
+func (c *Collection) freeBox() int {
+	for i, box := range c.Boxes {
+		if len(box) < BoxSize {
+			return i + 1
+		}
+	}
+	c.Boxes = append(c.Boxes, []int{})
+	return len(c.Boxes)
+}
+
+// Where finds the pokemon with the given ID, box 0 is the party
+func (c *Collection) Where(id int) (box, slot int, err error) {
+	if slot := slices.Index(c.Party, id); slot >= 0 {
+		return 0, slot, nil
+	}
+	for i, ids := range c.Boxes {
+		if slot := slices.Index(ids, id); slot >= 0 {
+			return i + 1, slot, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%w: #%v", ErrNotOwned, id)
+}
+
+// unstore takes id out of the party or whichever box it's in
+func (c *Collection) unstore(id int) {
+	c.Party = slices.DeleteFunc(c.Party, func(i int) bool { return i == id })
+	for i := range c.Boxes {
+		c.Boxes[i] = slices.DeleteFunc(c.Boxes[i], func(i int) bool { return i == id })
+	}
+	c.trimBoxes()
+}
+
+// empty boxes at the end go away, the ones in between keep their numbers
+func (c *Collection) trimBoxes() {
+	for len(c.Boxes) > 0 && len(c.Boxes[len(c.Boxes)-1]) == 0 {
+		c.Boxes = c.Boxes[:len(c.Boxes)-1]
+	}
+}
+
+// lastInParty reports whether id is the only pokemon left in the party
+func (c *Collection) lastInParty(id int) bool {
+	return len(c.Party) == 1 && c.Party[0] == id
+}
+
+// Lead is the first pokemon in the party, nil when the party is empty
+func (c *Collection) Lead() *Owned {
+	if len(c.Party) == 0 {
+		return nil
+	}
+	lead, err := c.Get(c.Party[0])
+	if err != nil {
+		return nil
+	}
+	return lead
+}
+
+// InParty returns the party in order
+func (c *Collection) InParty() []Owned {
+	return c.resolve(c.Party)
+}
+
+// Box returns the pokemon in pc box n. every box up to one past the last
+// used one exists, it's just empty.
+func (c *Collection) Box(n int) ([]Owned, error) {
+	if n < 1 || n > len(c.Boxes)+1 {
+		return nil, fmt.Errorf("there's no box %v, boxes go from 1 to %v", n, len(c.Boxes)+1)
+	}
+	if n > len(c.Boxes) {
+		return []Owned{}, nil
+	}
+	return c.resolve(c.Boxes[n-1]), nil
+}
+
+func (c *Collection) resolve(ids []int) []Owned {
+	result := make([]Owned, 0, len(ids))
+	for _, id := range ids {
+		if o, err := c.Get(id); err == nil {
+			result = append(result, *o)
+		}
+	}
+	return result
+}
+
+// Deposit moves a party pokemon into pc box n, or the first box with room
+// when n is 0. the last pokemon in the party has to stay.
+func (c *Collection) Deposit(id, n int) (int, error) {
+	box, _, err := c.Where(id)
+	if err != nil {
+		return 0, err
+	}
+	if box != 0 {
+		return 0, fmt.Errorf("#%v is already in box %v", id, box)
+	}
+	if c.lastInParty(id) {
+		return 0, ErrLastInParty
+	}
+	if n == 0 {
+		n = c.freeBox()
+	}
+	stored, err := c.Box(n)
+	if err != nil {
+		return 0, err
+	}
+	if len(stored) >= BoxSize {
+		return 0, fmt.Errorf("box %v is full", n)
+	}
+	if n > len(c.Boxes) {
+		c.Boxes = append(c.Boxes, []int{})
+	}
+	c.Party = slices.DeleteFunc(c.Party, func(i int) bool { return i == id })
+	c.Boxes[n-1] = append(c.Boxes[n-1], id)
+	return n, nil
+}
+
+// Withdraw moves a pokemon from its pc box to the end of the party
+func (c *Collection) Withdraw(id int) error {
+	box, _, err := c.Where(id)
+	if err != nil {
+		return err
+	}
+	if box == 0 {
+		return fmt.Errorf("#%v is already in your party", id)
+	}
+	if len(c.Party) >= PartySize {
+		return ErrPartyFull
+	}
+	c.unstore(id)
+	c.Party = append(c.Party, id)
+	return nil
+}
+
+// Swap exchanges the places of two pokemon, wherever they are. swapping
+// with the lead is how a pokemon becomes the new lead.
+func (c *Collection) Swap(a, b int) error {
+	boxA, slotA, err := c.Where(a)
+	if err != nil {
+		return err
+	}
+	boxB, slotB, err := c.Where(b)
+	if err != nil {
+		return err
+	}
+	*c.slot(boxA, slotA), *c.slot(boxB, slotB) = b, a
+	return nil
+}
+
+func (c *Collection) slot(box, slot int) *int {
+	if box == 0 {
+		return &c.Party[slot]
+	}
+	return &c.Boxes[box-1][slot]
+}
+
+// Tidy makes sure every pokemon has exactly one place: ids that aren't
+// owned are dropped and pokemon without a place are stored like new
+// catches. used for saves from before the party existed.
+func (c *Collection) Tidy() {
+	seen := map[int]bool{}
+	keep := func(id int) bool {
+		if _, err := c.Get(id); err != nil || seen[id] {
+			return false
+		}
+		seen[id] = true
+		return true
+	}
+	tidy := func(ids []int) []int {
+		return slices.DeleteFunc(ids, func(id int) bool { return !keep(id) })
+	}
+	c.Party = tidy(c.Party)
+	for i := range c.Boxes {
+		c.Boxes[i] = tidy(c.Boxes[i])
+	}
+	c.trimBoxes()
+	if len(c.Party) > PartySize {
+		overflow := slices.Clone(c.Party[PartySize:])
+		c.Party = c.Party[:PartySize]
+		for _, id := range overflow {
+			c.store(id)
+		}
+	}
+	for _, o := range c.Pokemon {
+		if !seen[o.ID] {
+			seen[o.ID] = true
+			c.store(o.ID)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package collection
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestAddFillsPartyThenBoxes(t *testing.T) {
+	c := Collection{}
+	for range PartySize {
+		if _, box := c.Add(Owned{Species: "magikarp"}); box != 0 {
+			t.Fatalf("expected the party to have room, got box %v", box)
+		}
+	}
+	seventh, box := c.Add(Owned{Species: "psyduck"})
+	if box != 1 {
+		t.Errorf("expected a full party to send catches to box 1, got %v", box)
+	}
+	if b, slot, err := c.Where(seventh.ID); b != 1 || slot != 0 || err != nil {
+		t.Errorf("unexpected place for #%v: box %v slot %v %v", seventh.ID, b, slot, err)
+	}
+	if lead := c.Lead(); lead == nil || lead.ID != 1 {
+		t.Errorf("expected #1 to lead, got %v", lead)
+	}
+
+	// releasing frees the place too
+	c.Release(1)
+	if lead := c.Lead(); lead == nil || lead.ID != 2 {
+		t.Errorf("expected #2 to lead after releasing #1, got %v", lead)
+	}
+	c.Release(seventh.ID)
+	if len(c.Boxes) != 0 {
+		t.Errorf("expected the empty box to go away, got %v", c.Boxes)
+	}
+}
+
+func TestDepositWithdrawSwap(t *testing.T) {
+	c := Collection{}
+	c.Add(Owned{Species: "pikachu"})
+	c.Add(Owned{Species: "psyduck"})
+
+	if box, err := c.Deposit(2, 0); box != 1 || err != nil {
+		t.Fatalf("expected psyduck in box 1, got %v %v", box, err)
+	}
+	if _, err := c.Deposit(1, 0); err == nil {
+		t.Errorf("expected the last party pokemon to have to stay")
+	}
+	if _, err := c.Deposit(2, 0); err == nil {
+		t.Errorf("expected an error depositing a boxed pokemon")
+	}
+	if _, err := c.Box(3); err == nil {
+		t.Errorf("expected an error for a box past the next empty one")
+	}
+	if stored, err := c.Box(2); len(stored) != 0 || err != nil {
+		t.Errorf("expected box 2 to be empty, got %v %v", stored, err)
+	}
+
+	// swapping across the party and a box trades places
+	if err := c.Swap(1, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(c.Party, []int{2}) || !slices.Equal(c.Boxes[0], []int{1}) {
+		t.Errorf("unexpected places after swapping: %v %v", c.Party, c.Boxes)
+	}
+
+	if err := c.Withdraw(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(c.Party, []int{2, 1}) || len(c.Boxes) != 0 {
+		t.Errorf("unexpected places after withdrawing: %v %v", c.Party, c.Boxes)
+	}
+	for range PartySize - 2 {
+		c.Add(Owned{Species: "magikarp"})
+	}
+	extra, _ := c.Add(Owned{Species: "magikarp"})
+	if err := c.Withdraw(extra.ID); !errors.Is(err, ErrPartyFull) {
+		t.Errorf("expected ErrPartyFull, got %v", err)
+	}
+	if err := c.Swap(1, 99); !errors.Is(err, ErrNotOwned) {
+		t.Errorf("expected ErrNotOwned, got %v", err)
+	}
+}
+
+func TestTidy(t *testing.T) {
+	c := Collection{NextID: 4, Pokemon: []Owned{{ID: 1}, {ID: 2}, {ID: 3}}}
+	c.Party = []int{2, 2, 7}
+	c.Boxes = [][]int{{9}}
+	c.Tidy()
+	if !slices.Equal(c.Party, []int{2, 1, 3}) || len(c.Boxes) != 0 {
+		t.Errorf("unexpected places after tidying: %v %v", c.Party, c.Boxes)
+	}
+}
+
+func TestReleaseKeepsALead(t *testing.T) {
+	c := Collection{}
+	c.Add(Owned{Species: "pikachu"})
+	c.Add(Owned{Species: "psyduck"})
+	c.Deposit(2, 0)
+
+	if _, err := c.Release(1); !errors.Is(err, ErrLastInParty) {
+		t.Errorf("expected ErrLastInParty, got %v", err)
+	}
+	if lead := c.Lead(); lead == nil || lead.ID != 1 {
+		t.Errorf("expected #1 to still lead, got %v", lead)
+	}
+	if _, err := c.Release(2); err != nil {
+		t.Errorf("unexpected error releasing a boxed pokemon: %v", err)
+	}
+}
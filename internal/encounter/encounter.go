@@ -0,0 +1,134 @@
+// Package encounter turns a location area's encounter data into wild pokemon:
+// which one shows up is weighted by each encounter's chance and its level is
+// rolled between the encounter's min and max level.
+package encounter
+
+import (
+	"math/rand"
+
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+// Slot is one way of meeting a pokemon in an area
+type Slot struct {
+	Pokemon  string
+	Version  string
+	Method   string
+	Chance   int // weight of the slot, percent in the API
+	MinLevel int
+	MaxLevel int
+}
+
+// Wild is a pokemon that showed up
+type Wild struct {
+	Pokemon string
+	Level   int
+	Method  string
+}
+
+// Table is every encounter slot of an area
+type Table struct {
+	Area  string
+	Slots []Slot
+}
+
+// NewTable flattens the encounter details of area, across every version,
+// into one table
+func NewTable(area pokeapi.PokemonInArea) Table {
+	table := Table{Area: area.Name}
+	for _, enc := range area.PokemonEncounters {
+		for _, version := range enc.VersionDetails {
+			for _, detail := range version.EncounterDetails {
+				table.Slots = append(table.Slots, Slot{
+					Pokemon:  enc.Pokemon.Name,
+					Version:  version.Version.Name,
+					Method:   detail.Method.Name,
+					Chance:   detail.Chance,
+					MinLevel: detail.MinLevel,
+					MaxLevel: detail.MaxLevel,
+				})
+			}
+		}
+	}
+	return table
+}
+
+// Has reports whether pokemon can be met in the area
+func (t Table) Has(pokemon string) bool {
+	for _, slot := range t.Slots {
+		if slot.Pokemon == pokemon {
+			return true
+		}
+	}
+	return false
+}
+
+// Pokemon lists the pokemon that can be met in the area, in table order
+func (t Table) Pokemon() []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, slot := range t.Slots {
+		if !seen[slot.Pokemon] {
+			seen[slot.Pokemon] = true
+			names = append(names, slot.Pokemon)
+		}
+	}
+	return names
+}
+
+// Draw picks a wild pokemon, each slot weighted by its chance.
+// it returns false when the area has nothing to meet.
+func (t Table) Draw(rng *rand.Rand) (Wild, bool) {
+	return draw(t.Slots, rng)
+}
+
+// Meet picks one of pokemon's slots, weighted by chance, and rolls its level.
+// it returns false when pokemon can't be met in the area.
+func (t Table) Meet(pokemon string, rng *rand.Rand) (Wild, bool) {
+	slots := []Slot{}
+	for _, slot := range t.Slots {
+		if slot.Pokemon == pokemon {
+			slots = append(slots, slot)
+		}
+	}
+	return draw(slots, rng)
+}
+
+func draw(slots []Slot, rng *rand.Rand) (Wild, bool) {
+	if len(slots) == 0 {
+		return Wild{}, false
+	}
+	total := 0
+	for _, slot := range slots {
+		total += max(slot.Chance, 0)
+	}
+
+	picked := slots[0]
+	if total > 0 {
+		roll := rng.Intn(total)
+		for _, slot := range slots {
+			if roll < max(slot.Chance, 0) {
+				picked = slot
+				break
+			}
+			roll -= max(slot.Chance, 0)
+		}
+	} else {
+		// no chances to go on, every slot is as likely
+		picked = slots[rng.Intn(len(slots))]
+	}
+
+	return Wild{
+		Pokemon: picked.Pokemon,
+		Level:   rollLevel(picked, rng),
+		Method:  picked.Method,
+	}, true
+}
+
+func rollLevel(slot Slot, rng *rand.Rand) int {
+	low, high := max(slot.MinLevel, 1), max(slot.MaxLevel, 1)
+	if high < low {
+		low, high = high, low
+	}
+	return low + rng.Intn(high-low+1)
+}
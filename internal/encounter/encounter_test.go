@@ -0,0 +1,57 @@
+package encounter
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDrawWeighting(t *testing.T) {
+	table := Table{Slots: []Slot{
+		{Pokemon: "zubat", Chance: 90, MinLevel: 2, MaxLevel: 4},
+		{Pokemon: "geodude", Chance: 10, MinLevel: 7, MaxLevel: 7},
+	}}
+	rng := rand.New(rand.NewSource(1))
+
+	const draws = 10000
+	counts := make(map[string]int)
+	for i := 0; i < draws; i++ {
+		wild, ok := table.Draw(rng)
+		if !ok {
+			t.Fatalf("expected a wild pokemon")
+		}
+		counts[wild.Pokemon]++
+		switch wild.Pokemon {
+		case "zubat":
+			if wild.Level < 2 || wild.Level > 4 {
+				t.Fatalf("zubat level out of range: %v", wild.Level)
+			}
+		case "geodude":
+			if wild.Level != 7 {
+				t.Fatalf("geodude level out of range: %v", wild.Level)
+			}
+		}
+	}
+	if rate := float64(counts["geodude"]) / draws; math.Abs(rate-0.1) > 0.02 {
+		t.Errorf("expected geodude about 10%% of the time, got %.3f", rate)
+	}
+}
+
+func TestMeet(t *testing.T) {
+	table := Table{Slots: []Slot{
+		{Pokemon: "zubat", Chance: 90, MinLevel: 2, MaxLevel: 4},
+		{Pokemon: "geodude", Chance: 10, MinLevel: 7, MaxLevel: 7},
+	}}
+	rng := rand.New(rand.NewSource(1))
+
+	wild, ok := table.Meet("geodude", rng)
+	if !ok || wild.Pokemon != "geodude" || wild.Level != 7 {
+		t.Errorf("unexpected encounter: %+v %v", wild, ok)
+	}
+	if _, ok := table.Meet("mewtwo", rng); ok {
+		t.Errorf("expected mewtwo not to be in the area")
+	}
+	if _, ok := (Table{}).Draw(rng); ok {
+		t.Errorf("expected an empty table to have nothing to draw")
+	}
+}
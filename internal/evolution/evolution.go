@@ -0,0 +1,165 @@
+// Package evolution decides when an owned pokemon evolves, from the
+// conditions in its species' evolution chain.
+package evolution
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+// Candidate is what we know about a pokemon that might evolve
+type Candidate struct {
+	Species string
+	Level   int
+	Moves   []string
+	Time    time.Time // for evolutions that only happen by day or by night
+}
+
+// Find returns the link for species somewhere in the chain
+func Find(link pokeapi.ChainLink, species string) (pokeapi.ChainLink, bool) {
+	if link.Species.Name == species {
+		return link, true
+	}
+	for _, next := range link.EvolvesTo {
+		if found, ok := Find(next, species); ok {
+			return found, true
+		}
+	}
+	return pokeapi.ChainLink{}, false
+}
+
+// Next returns the species c evolves into by leveling up, if any.
+// when several evolutions are possible the first one in the chain wins.
+func Next(chain pokeapi.ChainLink, c Candidate) (string, bool) {
+	link, ok := Find(chain, c.Species)
+	if !ok {
+		return "", false
+	}
+	for _, next := range link.EvolvesTo {
+		for _, detail := range next.EvolutionDetails {
+			if Met(detail, c) {
+				return next.Species.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Met reports whether c meets every condition of a level up evolution.
+// conditions we don't track yet (happiness, held items, trades, the party...)
+// are never met, so those evolutions just don't happen for now.
+func Met(detail pokeapi.EvolutionDetail, c Candidate) bool {
+	if detail.Trigger.Name != "level-up" {
+		return false
+	}
+	if detail.Item != nil || detail.HeldItem != nil || detail.KnownMoveType != nil ||
+		detail.Location != nil || detail.PartySpecies != nil || detail.PartyType != nil ||
+		detail.TradeSpecies != nil || detail.Gender != nil || detail.MinHappiness != nil ||
+		detail.MinBeauty != nil || detail.MinAffection != nil || detail.RelativePhysicalStats != nil ||
+		detail.NeedsOverworldRain || detail.TurnUpsideDown {
+		return false
+	}
+	if detail.MinLevel != nil && c.Level < *detail.MinLevel {
+		return false
+	}
+	if detail.KnownMove != nil && !slices.Contains(c.Moves, detail.KnownMove.Name) {
+		return false
+	}
+	switch detail.TimeOfDay {
+	case "day":
+		return !isNight(c.Time)
+	case "night":
+		return isNight(c.Time)
+	case "":
+		return true
+	}
+	// dusk and anything newer
+	return false
+}
+
+func isNight(t time.Time) bool {
+	return t.Hour() < 6 || t.Hour() >= 18
+}
+
+// Describe says in a few words how an evolution is triggered, like "Lv. 16",
+// "use thunder-stone" or "level up with happiness 160+ during the night"
+func Describe(detail pokeapi.EvolutionDetail) string {
+	conditions := []string{}
+	add := func(format string, args ...any) {
+		conditions = append(conditions, fmt.Sprintf(format, args...))
+	}
+	if detail.MinHappiness != nil {
+		add("with happiness %v+", *detail.MinHappiness)
+	}
+	if detail.MinAffection != nil {
+		add("with affection %v+", *detail.MinAffection)
+	}
+	if detail.MinBeauty != nil {
+		add("with beauty %v+", *detail.MinBeauty)
+	}
+	if detail.KnownMove != nil {
+		add("knowing %v", detail.KnownMove.Name)
+	}
+	if detail.KnownMoveType != nil {
+		add("knowing a %v move", detail.KnownMoveType.Name)
+	}
+	if detail.HeldItem != nil {
+		add("holding %v", detail.HeldItem.Name)
+	}
+	if detail.Location != nil {
+		add("at %v", detail.Location.Name)
+	}
+	if detail.PartySpecies != nil {
+		add("with %v in the party", detail.PartySpecies.Name)
+	}
+	if detail.PartyType != nil {
+		add("with a %v pokemon in the party", detail.PartyType.Name)
+	}
+	if detail.TradeSpecies != nil {
+		add("for a %v", detail.TradeSpecies.Name)
+	}
+	if detail.RelativePhysicalStats != nil {
+		switch *detail.RelativePhysicalStats {
+		case 1:
+			add("with attack higher than defense")
+		case -1:
+			add("with attack lower than defense")
+		default:
+			add("with attack equal to defense")
+		}
+	}
+	if detail.Gender != nil {
+		// PokeAPI genders: 1 is female, 2 is male
+		gender := "male"
+		if *detail.Gender == 1 {
+			gender = "female"
+		}
+		add("if %v", gender)
+	}
+	if detail.TimeOfDay != "" {
+		add("during the %v", detail.TimeOfDay)
+	}
+	if detail.NeedsOverworldRain {
+		add("while it rains")
+	}
+	if detail.TurnUpsideDown {
+		add("holding the console upside down")
+	}
+
+	how := strings.ReplaceAll(detail.Trigger.Name, "-", " ")
+	switch detail.Trigger.Name {
+	case "level-up":
+		if detail.MinLevel != nil {
+			how = fmt.Sprintf("Lv. %v", *detail.MinLevel)
+		}
+	case "use-item":
+		if detail.Item != nil {
+			how = "use " + detail.Item.Name
+		}
+	}
+	return strings.Join(append([]string{how}, conditions...), " ")
+}
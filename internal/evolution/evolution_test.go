@@ -0,0 +1,100 @@
+package evolution
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+// eevee evolves by level up at night or by day once it's happy, and by stones
+const eeveeChain = `{"species": {"name": "eevee"}, "evolution_details": [], "evolves_to": [
+	{"species": {"name": "vaporeon"}, "evolution_details": [{"trigger": {"name": "use-item"}, "item": {"name": "water-stone"}}], "evolves_to": []},
+	{"species": {"name": "umbreon"}, "evolution_details": [{"trigger": {"name": "level-up"}, "min_happiness": 160, "time_of_day": "night"}], "evolves_to": []},
+	{"species": {"name": "sylveon"}, "evolution_details": [{"trigger": {"name": "level-up"}, "known_move_type": {"name": "fairy"}}], "evolves_to": []}
+]}`
+
+// poliwag evolves at 25 and again by trade or by stone
+const poliwagChain = `{"species": {"name": "poliwag"}, "evolution_details": [], "evolves_to": [
+	{"species": {"name": "poliwhirl"}, "evolution_details": [{"trigger": {"name": "level-up"}, "min_level": 25}], "evolves_to": [
+		{"species": {"name": "politoed"}, "evolution_details": [{"trigger": {"name": "trade"}}], "evolves_to": []},
+		{"species": {"name": "poliwrath"}, "evolution_details": [{"trigger": {"name": "use-item"}, "item": {"name": "water-stone"}}], "evolves_to": []}
+	]}
+]}`
+
+// tangela needs to know ancient power
+const tangelaChain = `{"species": {"name": "tangela"}, "evolution_details": [], "evolves_to": [
+	{"species": {"name": "tangrowth"}, "evolution_details": [{"trigger": {"name": "level-up"}, "known_move": {"name": "ancient-power"}}], "evolves_to": []}
+]}`
+
+func chain(t *testing.T, body string) pokeapi.ChainLink {
+	t.Helper()
+	link := pokeapi.ChainLink{}
+	if err := json.Unmarshal([]byte(body), &link); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return link
+}
+
+func TestNext(t *testing.T) {
+	noon := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		chain     string
+		candidate Candidate
+		expected  string
+	}{
+		{chain: poliwagChain, candidate: Candidate{Species: "poliwag", Level: 24}, expected: ""},
+		{chain: poliwagChain, candidate: Candidate{Species: "poliwag", Level: 25}, expected: "poliwhirl"},
+		{chain: poliwagChain, candidate: Candidate{Species: "poliwhirl", Level: 60}, expected: ""},
+		{chain: poliwagChain, candidate: Candidate{Species: "poliwrath", Level: 60}, expected: ""},
+		{chain: poliwagChain, candidate: Candidate{Species: "pikachu", Level: 60}, expected: ""},
+		{chain: eeveeChain, candidate: Candidate{Species: "eevee", Level: 60, Time: noon}, expected: ""},
+		{chain: tangelaChain, candidate: Candidate{Species: "tangela", Level: 30, Moves: []string{"vine-whip"}}, expected: ""},
+		{chain: tangelaChain, candidate: Candidate{Species: "tangela", Level: 30, Moves: []string{"vine-whip", "ancient-power"}}, expected: "tangrowth"},
+	}
+	for _, c := range cases {
+		actual, ok := Next(chain(t, c.chain), c.candidate)
+		if ok != (c.expected != "") || actual != c.expected {
+			t.Errorf("%+v: expected: %q, but got %q.", c.candidate, c.expected, actual)
+		}
+	}
+}
+
+func TestMetTimeOfDay(t *testing.T) {
+	detail := pokeapi.EvolutionDetail{TimeOfDay: "night"}
+	detail.Trigger.Name = "level-up"
+	night := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	noon := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if !Met(detail, Candidate{Time: night}) {
+		t.Errorf("expected a night evolution to happen at night")
+	}
+	if Met(detail, Candidate{Time: noon}) {
+		t.Errorf("expected a night evolution not to happen at noon")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	cases := []struct {
+		detail   string
+		expected string
+	}{
+		{detail: `{"trigger": {"name": "level-up"}, "min_level": 16}`, expected: "Lv. 16"},
+		{detail: `{"trigger": {"name": "use-item"}, "item": {"name": "thunder-stone"}}`, expected: "use thunder-stone"},
+		{detail: `{"trigger": {"name": "trade"}}`, expected: "trade"},
+		{detail: `{"trigger": {"name": "trade"}, "held_item": {"name": "metal-coat"}}`, expected: "trade holding metal-coat"},
+		{detail: `{"trigger": {"name": "level-up"}, "min_happiness": 160, "time_of_day": "night"}`, expected: "level up with happiness 160+ during the night"},
+		{detail: `{"trigger": {"name": "level-up"}, "min_level": 20, "relative_physical_stats": 1}`, expected: "Lv. 20 with attack higher than defense"},
+		{detail: `{"trigger": {"name": "level-up"}, "known_move": {"name": "ancient-power"}}`, expected: "level up knowing ancient-power"},
+		{detail: `{"trigger": {"name": "shed"}}`, expected: "shed"},
+	}
+	for _, c := range cases {
+		detail := pokeapi.EvolutionDetail{}
+		if err := json.Unmarshal([]byte(c.detail), &detail); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if actual := Describe(detail); actual != c.expected {
+			t.Errorf("Expected: %q, but got %q.", c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,506 @@
+// Package fakeapi is a tiny in-process stand-in for PokeAPI, for tests that
+// need to run commands without touching the network.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PageSize is how many location areas the fake list endpoint returns per page
+const PageSize = 2
+
+// Server serves location areas and pokemon from memory.
+// the zero value isn't usable, build one with New.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	areas     []string                 // location area names in list order
+	types     map[string]typeRelations // damage relations by type name
+	resources map[string]any           // "location-area/canalave-city-area" -> JSON body
+	requests  map[string]int           // request counts by path, for asserting on caching
+}
+
+// New starts a fake API seeded with a handful of areas and pokemon.
+// close it with Close when the test is done.
+func New() *Server {
+	s := &Server{
+		resources: make(map[string]any),
+		requests:  make(map[string]int),
+		types:     make(map[string]typeRelations),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	s.AddLocationArea("canalave-city-area", "tentacool", "pikachu")
+	s.AddLocationArea("eterna-city-area", "psyduck")
+	s.AddLocationArea("pastoria-city-area", "magikarp", "pikachu")
+	s.AddPokemon(Pokemon{ID: 172, Name: "pichu", BaseExperience: 41, Height: 3, Weight: 20, CaptureRate: 190,
+		Stats: [6]int{20, 40, 15, 35, 35, 60}, Types: []string{"electric"}, Generation: "generation-ii",
+		Moves: []LevelUpMove{{"thunder-shock", 1}, {"charm", 1}}})
+	s.AddPokemon(Pokemon{ID: 25, Name: "pikachu", BaseExperience: 112, Height: 4, Weight: 60, CaptureRate: 190,
+		Stats: [6]int{35, 55, 40, 50, 50, 90}, Types: []string{"electric"},
+		Moves: []LevelUpMove{{"thunder-shock", 1}, {"growl", 1}, {"tail-whip", 5}, {"thunder-wave", 10}, {"quick-attack", 13}}})
+	s.AddPokemon(Pokemon{ID: 26, Name: "raichu", BaseExperience: 218, Height: 8, Weight: 300, CaptureRate: 75,
+		Stats: [6]int{60, 90, 55, 90, 80, 110}, Types: []string{"electric"},
+		Moves: []LevelUpMove{{"thunder-shock", 1}, {"tail-whip", 1}, {"quick-attack", 1}, {"thunderbolt", 1}}})
+	s.AddPokemon(Pokemon{ID: 72, Name: "tentacool", BaseExperience: 67, Height: 9, Weight: 455, CaptureRate: 190,
+		Stats: [6]int{40, 40, 35, 50, 100, 70}, Types: []string{"water", "poison"},
+		Moves: []LevelUpMove{{"poison-sting", 1}, {"supersonic", 6}}})
+	s.AddPokemon(Pokemon{ID: 54, Name: "psyduck", BaseExperience: 64, Height: 8, Weight: 196, CaptureRate: 190,
+		Stats: [6]int{50, 52, 48, 65, 50, 55}, Types: []string{"water"},
+		Moves: []LevelUpMove{{"water-sport", 1}, {"scratch", 1}, {"tail-whip", 5}, {"disable", 9}, {"confusion", 14}}})
+	s.AddPokemon(Pokemon{ID: 55, Name: "golduck", BaseExperience: 175, Height: 17, Weight: 766, CaptureRate: 75,
+		Stats: [6]int{80, 82, 78, 95, 80, 85}, Types: []string{"water"},
+		Moves: []LevelUpMove{{"water-sport", 1}, {"scratch", 1}, {"tail-whip", 1}, {"confusion", 14}}})
+	s.AddPokemon(Pokemon{ID: 129, Name: "magikarp", BaseExperience: 40, Height: 9, Weight: 100, CaptureRate: 255,
+		Stats: [6]int{20, 10, 55, 15, 20, 80}, Types: []string{"water"},
+		Moves: []LevelUpMove{{"splash", 1}, {"tackle", 15}}})
+	s.AddPokemon(Pokemon{ID: 130, Name: "gyarados", BaseExperience: 189, Height: 65, Weight: 2350, CaptureRate: 45,
+		Stats: [6]int{95, 125, 79, 60, 100, 81}, Types: []string{"water", "flying"},
+		Moves: []LevelUpMove{{"bite", 1}, {"dragon-rage", 20}}})
+	s.AddEvolutionChain(10, "pichu",
+		Evolution{Species: "pikachu", From: "pichu", Trigger: "level-up", MinHappiness: 220},
+		Evolution{Species: "raichu", From: "pikachu", Trigger: "use-item", Item: "thunder-stone"})
+	s.AddPokemon(Pokemon{ID: 133, Name: "eevee", BaseExperience: 65, Height: 3, Weight: 65,
+		Stats: [6]int{55, 55, 50, 45, 65, 55}, Types: []string{"normal"},
+		Moves: []LevelUpMove{{"tackle", 1}, {"tail-whip", 1}, {"quick-attack", 8}}})
+	s.AddEvolutionChain(67, "eevee",
+		Evolution{Species: "vaporeon", From: "eevee", Trigger: "use-item", Item: "water-stone"},
+		Evolution{Species: "jolteon", From: "eevee", Trigger: "use-item", Item: "thunder-stone"},
+		Evolution{Species: "flareon", From: "eevee", Trigger: "use-item", Item: "fire-stone"},
+		Evolution{Species: "espeon", From: "eevee", Trigger: "level-up", MinHappiness: 160, TimeOfDay: "day"},
+		Evolution{Species: "umbreon", From: "eevee", Trigger: "level-up", MinHappiness: 160, TimeOfDay: "night"},
+		Evolution{Species: "leafeon", From: "eevee", Trigger: "use-item", Item: "leaf-stone"},
+		Evolution{Species: "glaceon", From: "eevee", Trigger: "use-item", Item: "ice-stone"},
+		Evolution{Species: "sylveon", From: "eevee", Trigger: "level-up", KnownMoveType: "fairy"},
+		Evolution{Species: "sylveon", From: "eevee", Trigger: "level-up", MinHappiness: 160, KnownMoveType: "fairy"})
+	s.AddEvolutionChain(26, "psyduck", Evolution{Species: "golduck", From: "psyduck", Trigger: "level-up", MinLevel: 33})
+	s.AddEvolutionChain(63, "magikarp", Evolution{Species: "gyarados", From: "magikarp", Trigger: "level-up", MinLevel: 20})
+	s.AddPokemon(Pokemon{ID: 412, Name: "burmy", BaseExperience: 45, Height: 2, Weight: 34, CaptureRate: 120, Generation: "generation-iv",
+		Stats: [6]int{40, 29, 45, 29, 45, 36}, Types: []string{"bug"},
+		Moves: []LevelUpMove{{"protect", 1}, {"tackle", 10}}})
+	// wormadam only comes as forms, there is no pokemon called wormadam
+	s.AddPokemon(Pokemon{ID: 413, Name: "wormadam-plant", Species: "wormadam", BaseExperience: 148, Height: 5, Weight: 65, Generation: "generation-iv",
+		Stats: [6]int{60, 59, 85, 79, 105, 36}, Types: []string{"bug", "grass"},
+		Moves: []LevelUpMove{{"tackle", 1}, {"protect", 1}, {"confusion", 20}}})
+	s.AddPokemon(Pokemon{ID: 10004, Name: "wormadam-sandy", Species: "wormadam", BaseExperience: 148, Height: 5, Weight: 65, Generation: "generation-iv",
+		Stats: [6]int{60, 79, 105, 59, 85, 36}, Types: []string{"bug", "ground"},
+		Moves: []LevelUpMove{{"tackle", 1}, {"protect", 1}, {"confusion", 20}}})
+	s.AddEvolutionChain(213, "burmy", Evolution{Species: "wormadam", From: "burmy", Trigger: "level-up", MinLevel: 20})
+	s.AddMove(Move{Name: "thunder-shock", Type: "electric", Class: "special", Power: 40, Accuracy: 100, PP: 30})
+	s.AddMove(Move{Name: "thunderbolt", Type: "electric", Class: "special", Power: 90, Accuracy: 100, PP: 15})
+	s.AddMove(Move{Name: "thunder-wave", Type: "electric", Class: "status", Accuracy: 90, PP: 20})
+	s.AddMove(Move{Name: "quick-attack", Type: "normal", Class: "physical", Power: 40, Accuracy: 100, PP: 30, Priority: 1})
+	s.AddMove(Move{Name: "scratch", Type: "normal", Class: "physical", Power: 40, Accuracy: 100, PP: 35})
+	s.AddMove(Move{Name: "tackle", Type: "normal", Class: "physical", Power: 40, Accuracy: 100, PP: 35})
+	s.AddMove(Move{Name: "growl", Type: "normal", Class: "status", Accuracy: 100, PP: 40})
+	s.AddMove(Move{Name: "tail-whip", Type: "normal", Class: "status", Accuracy: 100, PP: 30})
+	s.AddMove(Move{Name: "supersonic", Type: "normal", Class: "status", Accuracy: 55, PP: 20})
+	s.AddMove(Move{Name: "disable", Type: "normal", Class: "status", Accuracy: 100, PP: 20})
+	s.AddMove(Move{Name: "protect", Type: "normal", Class: "status", PP: 10, Priority: 4})
+	s.AddMove(Move{Name: "splash", Type: "normal", Class: "status", PP: 40})
+	s.AddMove(Move{Name: "charm", Type: "fairy", Class: "status", Accuracy: 100, PP: 20})
+	s.AddMove(Move{Name: "poison-sting", Type: "poison", Class: "physical", Power: 15, Accuracy: 100, PP: 35})
+	s.AddMove(Move{Name: "water-sport", Type: "water", Class: "status", PP: 15})
+	s.AddMove(Move{Name: "confusion", Type: "psychic", Class: "special", Power: 50, Accuracy: 100, PP: 25})
+	s.AddMove(Move{Name: "bite", Type: "dark", Class: "physical", Power: 60, Accuracy: 100, PP: 25})
+	s.AddMove(Move{Name: "dragon-rage", Type: "dragon", Class: "special", Accuracy: 100, PP: 10})
+	s.AddType("normal", nil, []string{"rock", "steel"}, []string{"ghost"})
+	s.AddType("electric", []string{"water", "flying"}, []string{"electric", "grass", "dragon"}, []string{"ground"})
+	s.AddType("water", []string{"fire", "ground", "rock"}, []string{"water", "grass", "dragon"}, nil)
+	s.AddType("poison", []string{"grass", "fairy"}, []string{"poison", "ground", "rock", "ghost"}, []string{"steel"})
+	s.AddType("psychic", []string{"fighting", "poison"}, []string{"psychic", "steel"}, []string{"dark"})
+	s.AddType("dark", []string{"psychic", "ghost"}, []string{"fighting", "dark", "fairy"}, nil)
+	s.AddType("dragon", []string{"dragon"}, []string{"steel"}, []string{"fairy"})
+	s.AddType("fairy", []string{"fighting", "dragon", "dark"}, []string{"fire", "poison", "steel"}, nil)
+	s.AddType("flying", []string{"grass", "fighting", "bug"}, []string{"electric", "rock", "steel"}, nil)
+	s.AddType("fire", []string{"grass", "ice", "bug", "steel"}, []string{"fire", "water", "rock", "dragon"}, nil)
+	s.AddType("grass", []string{"water", "ground", "rock"}, []string{"fire", "grass", "poison", "flying", "bug", "dragon", "steel"}, nil)
+	s.AddType("ground", []string{"fire", "electric", "poison", "rock", "steel"}, []string{"grass", "bug"}, []string{"flying"})
+	s.AddItem("poke-ball", 200, "standard-balls")
+	s.AddItem("great-ball", 600, "standard-balls")
+	s.AddItem("ultra-ball", 800, "standard-balls")
+	s.AddItem("master-ball", 0, "special-balls")
+	s.AddItem("potion", 200, "healing")
+	s.AddItem("super-potion", 700, "healing")
+	s.AddItem("hyper-potion", 1500, "healing")
+	return s
+}
+
+// URL of the API root, to hand to pokeapi.NewClient
+func (s *Server) BaseURL() string {
+	return s.Server.URL
+}
+
+// Requests reports how many times path (e.g. "/pokemon/pikachu") was asked for
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// Set serves body as JSON at path, e.g. Set("pokemon-species/pikachu", ...).
+// it is the escape hatch for anything the typed helpers don't cover.
+func (s *Server) Set(path string, body any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[strings.Trim(path, "/")] = body
+}
+
+// AddLocationArea adds an area to the end of the list with one encounter per
+// pokemon, each at 50% chance and levels 2 to 5
+func (s *Server) AddLocationArea(name string, pokemon ...string) {
+	encounters := []any{}
+	for _, poke := range pokemon {
+		encounters = append(encounters, map[string]any{
+			"pokemon": s.ref("pokemon", poke),
+			"version_details": []any{map[string]any{
+				"max_chance": 50,
+				"version":    map[string]any{"name": "diamond", "url": ""},
+				"encounter_details": []any{map[string]any{
+					"chance":    50,
+					"min_level": 2,
+					"max_level": 5,
+					"method":    map[string]any{"name": "walk", "url": ""},
+				}},
+			}},
+		})
+	}
+
+	s.mu.Lock()
+	s.areas = append(s.areas, name)
+	id := len(s.areas)
+	s.mu.Unlock()
+	s.Set("location-area/"+name, map[string]any{
+		"id":                 id,
+		"name":               name,
+		"pokemon_encounters": encounters,
+	})
+}
+
+// Pokemon is the subset of the pokemon endpoint the fake fills in
+type Pokemon struct {
+	ID             int
+	Name           string
+	BaseExperience int
+	Height         int
+	Weight         int
+	// hp, attack, defense, special-attack, special-defense, speed
+	Stats [6]int
+	Types []string
+	// from the species endpoint, 0 means 45 like most fully evolved pokemon
+	CaptureRate int
+	// learned by leveling up in the diamond-pearl version group
+	Moves []LevelUpMove
+	// from the species endpoint, empty means generation-i
+	Generation string
+	// the species this pokemon is a form of, empty means it's its own
+	// species. the first form added for a species is its default.
+	Species string
+}
+
+// LevelUpMove is a move learned by leveling up
+type LevelUpMove struct {
+	Name  string
+	Level int
+}
+
+// Move is the subset of the move endpoint the fake fills in
+type Move struct {
+	Name     string
+	Type     string
+	Class    string // physical, special or status
+	Power    int    // 0 is served as null
+	Accuracy int    // 0 is served as null
+	PP       int
+	Priority int
+}
+
+// Evolution is one step of an evolution chain, From evolves into Species.
+// only the conditions set are included in the evolution details.
+type Evolution struct {
+	Species      string
+	From         string
+	Trigger      string // level-up, use-item, trade...
+	MinLevel     int
+	MinHappiness int
+	Item         string
+	// the type of a move the pokemon has to know
+	KnownMoveType string
+	TimeOfDay     string // day or night
+}
+
+var statNames = [6]string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// AddPokemon serves p under both its name and its id, and its species
+// under the species name and the id of the first form added for it
+func (s *Server) AddPokemon(p Pokemon) {
+	stats := []any{}
+	for i, base := range p.Stats {
+		stats = append(stats, map[string]any{
+			"base_stat": base,
+			"effort":    0,
+			"stat":      s.ref("stat", statNames[i]),
+		})
+	}
+	types := []any{}
+	for i, t := range p.Types {
+		types = append(types, map[string]any{"slot": i + 1, "type": s.ref("type", t)})
+	}
+	speciesName := p.Species
+	if speciesName == "" {
+		speciesName = p.Name
+	}
+	moves := []any{}
+	for _, move := range p.Moves {
+		moves = append(moves, map[string]any{
+			"move": s.ref("move", move.Name),
+			"version_group_details": []any{map[string]any{
+				"level_learned_at":  move.Level,
+				"move_learn_method": s.ref("move-learn-method", "level-up"),
+				"version_group":     s.ref("version-group", "diamond-pearl"),
+			}},
+		})
+	}
+	body := map[string]any{
+		"id":              p.ID,
+		"name":            p.Name,
+		"base_experience": p.BaseExperience,
+		"height":          p.Height,
+		"weight":          p.Weight,
+		"stats":           stats,
+		"types":           types,
+		"moves":           moves,
+		"species":         s.ref("pokemon-species", speciesName),
+	}
+	s.Set("pokemon/"+p.Name, body)
+	s.Set("pokemon/"+strconv.Itoa(p.ID), body)
+
+	variety := map[string]any{"is_default": false, "pokemon": s.ref("pokemon", p.Name)}
+	s.mu.Lock()
+	species, ok := s.resources["pokemon-species/"+speciesName].(map[string]any)
+	if ok {
+		// another form of a species we already serve
+		species["varieties"] = append(species["varieties"].([]any), variety)
+	}
+	s.mu.Unlock()
+	if ok {
+		return
+	}
+	variety["is_default"] = true
+
+	captureRate := p.CaptureRate
+	if captureRate == 0 {
+		captureRate = 45
+	}
+	generation := p.Generation
+	if generation == "" {
+		generation = "generation-i"
+	}
+	species = map[string]any{
+		"id":           p.ID,
+		"name":         speciesName,
+		"capture_rate": captureRate,
+		"growth_rate":  s.ref("growth-rate", "medium"),
+		"generation":   s.ref("generation", generation),
+		"varieties":    []any{variety},
+	}
+	s.Set("pokemon-species/"+speciesName, species)
+	s.Set("pokemon-species/"+strconv.Itoa(p.ID), species)
+}
+
+// AddEvolutionChain serves an evolution chain starting at base and points
+// the species of every pokemon in it at the chain. the pokemon have to be
+// added first.
+func (s *Server) AddEvolutionChain(id int, base string, evolutions ...Evolution) {
+	chain := s.chainLink(base, nil, evolutions)
+	s.Set("evolution-chain/"+strconv.Itoa(id), map[string]any{"id": id, "chain": chain})
+
+	chainRef := map[string]any{"url": fmt.Sprintf("%v/evolution-chain/%v/", s.BaseURL(), id)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if species, ok := s.resources["pokemon-species/"+base].(map[string]any); ok {
+		species["evolution_chain"] = chainRef
+	}
+	for _, evolution := range evolutions {
+		if species, ok := s.resources["pokemon-species/"+evolution.Species].(map[string]any); ok {
+			species["evolution_chain"] = chainRef
+			species["evolves_from_species"] = s.ref("pokemon-species", evolution.From)
+		}
+	}
+}
+
+// chainLink builds the link for species and everything that evolves from
+// it. evolutions listed more than once for the same species become one link
+// with several ways to evolve.
+func (s *Server) chainLink(species string, ways []Evolution, evolutions []Evolution) map[string]any {
+	details := []any{}
+	for _, how := range ways {
+		detail := map[string]any{"trigger": s.ref("evolution-trigger", how.Trigger)}
+		if how.MinLevel != 0 {
+			detail["min_level"] = how.MinLevel
+		}
+		if how.MinHappiness != 0 {
+			detail["min_happiness"] = how.MinHappiness
+		}
+		if how.Item != "" {
+			detail["item"] = s.ref("item", how.Item)
+		}
+		if how.KnownMoveType != "" {
+			detail["known_move_type"] = s.ref("type", how.KnownMoveType)
+		}
+		detail["time_of_day"] = how.TimeOfDay
+		details = append(details, detail)
+	}
+
+	evolvesTo := []any{}
+	seen := make(map[string]bool)
+	for _, evolution := range evolutions {
+		if evolution.From != species || seen[evolution.Species] {
+			continue
+		}
+		seen[evolution.Species] = true
+		ways := []Evolution{}
+		for _, other := range evolutions {
+			if other.From == species && other.Species == evolution.Species {
+				ways = append(ways, other)
+			}
+		}
+		evolvesTo = append(evolvesTo, s.chainLink(evolution.Species, ways, evolutions))
+	}
+	return map[string]any{
+		"is_baby":           false,
+		"species":           s.ref("pokemon-species", species),
+		"evolution_details": details,
+		"evolves_to":        evolvesTo,
+	}
+}
+
+// AddMove serves a move
+func (s *Server) AddMove(m Move) {
+	body := map[string]any{
+		"name":         m.Name,
+		"power":        nil,
+		"accuracy":     nil,
+		"pp":           m.PP,
+		"priority":     m.Priority,
+		"type":         s.ref("type", m.Type),
+		"damage_class": s.ref("move-damage-class", m.Class),
+	}
+	if m.Power != 0 {
+		body["power"] = m.Power
+	}
+	if m.Accuracy != 0 {
+		body["accuracy"] = m.Accuracy
+	}
+	s.Set("move/"+m.Name, body)
+}
+
+// typeRelations are the damage relations of one type, by relation name
+// like "double_damage_to"
+type typeRelations map[string][]string
+
+// AddType serves a type that does double, half and no damage to the given
+// types. the damage_from side of every type involved is filled in to match,
+// types that are only ever defended against get served too.
+func (s *Server) AddType(name string, double, half, none []string) {
+	s.mu.Lock()
+	relations := func(t string) typeRelations {
+		if s.types[t] == nil {
+			s.types[t] = typeRelations{}
+		}
+		return s.types[t]
+	}
+	touched := []string{name}
+	for kind, targets := range map[string][]string{"double_damage": double, "half_damage": half, "no_damage": none} {
+		relations(name)[kind+"_to"] = append(relations(name)[kind+"_to"], targets...)
+		for _, target := range targets {
+			relations(target)[kind+"_from"] = append(relations(target)[kind+"_from"], name)
+			touched = append(touched, target)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, t := range touched {
+		s.mu.Lock()
+		damageRelations := map[string]any{}
+		for _, kind := range []string{"double_damage_to", "half_damage_to", "no_damage_to", "double_damage_from", "half_damage_from", "no_damage_from"} {
+			refs := []any{}
+			for _, other := range s.types[t][kind] {
+				refs = append(refs, s.ref("type", other))
+			}
+			damageRelations[kind] = refs
+		}
+		s.mu.Unlock()
+		s.Set("type/"+t, map[string]any{"name": t, "damage_relations": damageRelations})
+	}
+}
+
+// AddItem serves an item with the given cost
+func (s *Server) AddItem(name string, cost int, category string) {
+	s.Set("item/"+name, map[string]any{
+		"name":     name,
+		"cost":     cost,
+		"category": s.ref("item-category", category),
+	})
+}
+
+// ref is a named API resource pointing back at this server
+func (s *Server) ref(resource, name string) map[string]any {
+	return map[string]any{
+		"name": name,
+		"url":  fmt.Sprintf("%v/%v/%v/", s.BaseURL(), resource, name),
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+
+	s.mu.Lock()
+	s.requests["/"+path]++
+	body, ok := s.resources[path]
+	s.mu.Unlock()
+
+	if path == "location-area" {
+		body, ok = s.areaPage(r), true
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// areaPage is the paged location-area list, honouring offset like the real API
+func (s *Server) areaPage(r *http.Request) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	end := min(offset+PageSize, len(s.areas))
+	results := []any{}
+	for _, name := range s.areas[min(offset, end):end] {
+		results = append(results, s.ref("location-area", name))
+	}
+
+	page := map[string]any{
+		"count":    len(s.areas),
+		"next":     nil,
+		"previous": nil,
+		"results":  results,
+	}
+	if end < len(s.areas) {
+		page["next"] = fmt.Sprintf("%v/location-area/?offset=%v&limit=%v", s.BaseURL(), end, PageSize)
+	}
+	if offset > 0 {
+		page["previous"] = fmt.Sprintf("%v/location-area/?offset=%v&limit=%v", s.BaseURL(), max(offset-PageSize, 0), PageSize)
+	}
+	return page
+}
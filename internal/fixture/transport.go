@@ -0,0 +1,144 @@
+package fixture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Mode picks how a Transport treats requests
+type Mode string
+
+const (
+	// Live sends every request to the network untouched
+	Live Mode = "live"
+	// Record sends requests to the network and writes every response to the
+	// fixture directory
+	Record Mode = "record"
+	// Replay never touches the network, it only serves recorded fixtures
+	Replay Mode = "replay"
+)
+
+// ErrNoFixture is returned in replay mode for a request that was never recorded
+var ErrNoFixture = errors.New("fixture: no recording for request")
+
+// ParseMode turns a flag value into a Mode
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case Live, Record, Replay:
+		return Mode(s), nil
+	}
+	return "", fmt.Errorf("unknown http mode %q, expected live, record or replay", s)
+}
+
+// NewTransport wraps next according to mode. fixtures are read from and
+// written to dir, one file per method and URL. a nil next means
+// http.DefaultTransport.
+func NewTransport(mode Mode, dir string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	switch mode {
+	case Record:
+		return &recorder{dir: dir, next: next}
+	case Replay:
+		return &replayer{dir: dir}
+	}
+	return next
+}
+
+// recording is what ends up on disk for one request/response pair
+type recording struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// path is where the recording for req lives. the name is a hash because
+// URLs make poor file names.
+func path(dir string, req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.String()))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+type recorder struct {
+	dir  string
+	next http.RoundTripper
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a conditional request could record a bodiless 304, which is useless to
+	// replay, so always ask for the full response
+	req = req.Clone(req.Context())
+	req.Header.Del("If-None-Match")
+	req.Header.Del("If-Modified-Since")
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	data, err := json.MarshalIndent(recording{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Body:   string(body),
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path(r.dir, req), data, 0o644); err != nil {
+		return nil, fmt.Errorf("recording %v: %w", req.URL, err)
+	}
+	return resp, nil
+}
+
+type replayer struct {
+	dir string
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	data, err := os.ReadFile(path(r.dir, req))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %v %v", ErrNoFixture, req.Method, req.URL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rec := recording{}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("reading fixture for %v: %w", req.URL, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rec.Header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(rec.Body))),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
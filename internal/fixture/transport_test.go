@@ -0,0 +1,65 @@
+package fixture
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("expected conditional headers to be stripped while recording")
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("testdata"))
+	}))
+	dir := t.TempDir()
+
+	recordClient := &http.Client{Transport: NewTransport(Record, dir, nil)}
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/pokemon/pikachu", nil)
+	req.Header.Set("If-None-Match", `"v0"`)
+	resp, err := recordClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "testdata" {
+		t.Errorf("expected the live body while recording, got %q", body)
+	}
+
+	// the server is gone, replay has to serve from disk
+	srv.Close()
+	replayClient := &http.Client{Transport: NewTransport(Replay, dir, nil)}
+	resp, err = replayClient.Get(srv.URL + "/pokemon/pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "testdata" {
+		t.Errorf("unexpected replay: %v %q", resp.StatusCode, body)
+	}
+	if resp.Header.Get("ETag") != `"v1"` {
+		t.Errorf("expected headers to be replayed, got %v", resp.Header)
+	}
+
+	_, err = replayClient.Get(srv.URL + "/pokemon/mew")
+	if !errors.Is(err, ErrNoFixture) {
+		t.Errorf("expected ErrNoFixture for a miss, got %v", err)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, s := range []string{"live", "record", "replay"} {
+		if _, err := ParseMode(s); err != nil {
+			t.Errorf("unexpected error for %v: %v", s, err)
+		}
+	}
+	if _, err := ParseMode("offline"); err == nil {
+		t.Errorf("expected an error for an unknown mode")
+	}
+}
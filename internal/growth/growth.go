@@ -0,0 +1,93 @@
+// Package growth turns experience points into levels using the growth rate
+// curves every species is assigned to.
+package growth
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxLevel is as high as any pokemon can go
+const MaxLevel = 100
+
+// ErrUnknownRate is returned for a growth rate the package has no curve for
+var ErrUnknownRate = errors.New("unknown growth rate")
+
+// curves by their PokeAPI growth-rate name, each gives the total experience
+// needed to reach level n
+var curves = map[string]func(n int) int{
+	"fast": func(n int) int {
+		return 4 * n * n * n / 5
+	},
+	"medium": func(n int) int {
+		return n * n * n
+	},
+	"medium-slow": func(n int) int {
+		return 6*n*n*n/5 - 15*n*n + 100*n - 140
+	},
+	"slow": func(n int) int {
+		return 5 * n * n * n / 4
+	},
+	// known as erratic in the games
+	"slow-then-very-fast": func(n int) int {
+		cube := n * n * n
+		switch {
+		case n < 50:
+			return cube * (100 - n) / 50
+		case n < 68:
+			return cube * (150 - n) / 100
+		case n < 98:
+			return cube * ((1911 - 10*n) / 3) / 500
+		default:
+			return cube * (160 - n) / 100
+		}
+	},
+	// known as fluctuating in the games
+	"fast-then-very-slow": func(n int) int {
+		cube := n * n * n
+		switch {
+		case n < 15:
+			return cube * ((n+1)/3 + 24) / 50
+		case n < 36:
+			return cube * (n + 14) / 50
+		default:
+			return cube * (n/2 + 32) / 50
+		}
+	},
+}
+
+// Experience is the total experience a pokemon on the rate curve needs to
+// reach level. everything starts at 0 experience on level 1.
+func Experience(rate string, level int) (int, error) {
+	curve, ok := curves[rate]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownRate, rate)
+	}
+	if level <= 1 {
+		return 0, nil
+	}
+	return curve(min(level, MaxLevel)), nil
+}
+
+// Level is the level a pokemon on the rate curve is at with exp experience
+func Level(rate string, exp int) (int, error) {
+	if _, ok := curves[rate]; !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownRate, rate)
+	}
+	level := 1
+	for level < MaxLevel {
+		next, _ := Experience(rate, level+1)
+		if exp < next {
+			break
+		}
+		level++
+	}
+	return level, nil
+}
+
+// Yield is the experience for defeating, or catching, a pokemon with the
+// given base experience at level, following the Gen III formula for wild
+// pokemon
+func Yield(baseExperience, level int) int {
+	return max(baseExperience*level/7, 1)
+}
@@ -0,0 +1,84 @@
+package growth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExperience(t *testing.T) {
+	cases := []struct {
+		rate     string
+		level    int
+		expected int
+	}{
+		{rate: "medium", level: 1, expected: 0},
+		{rate: "medium", level: 5, expected: 125},
+		{rate: "medium", level: 100, expected: 1000000},
+		{rate: "fast", level: 100, expected: 800000},
+		{rate: "slow", level: 100, expected: 1250000},
+		{rate: "medium-slow", level: 2, expected: 9},
+		{rate: "medium-slow", level: 100, expected: 1059860},
+		{rate: "slow-then-very-fast", level: 100, expected: 600000},
+		{rate: "fast-then-very-slow", level: 100, expected: 1640000},
+		{rate: "medium", level: 150, expected: 1000000},
+	}
+	for _, c := range cases {
+		actual, err := Experience(c.rate, c.level)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if actual != c.expected {
+			t.Errorf("Experience(%v, %v): expected: %v, but got %v.", c.rate, c.level, c.expected, actual)
+		}
+	}
+
+	if _, err := Experience("sluggish", 5); !errors.Is(err, ErrUnknownRate) {
+		t.Errorf("expected ErrUnknownRate, got %v", err)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	cases := []struct {
+		rate     string
+		exp      int
+		expected int
+	}{
+		{rate: "medium", exp: 0, expected: 1},
+		{rate: "medium", exp: 124, expected: 4},
+		{rate: "medium", exp: 125, expected: 5},
+		{rate: "medium", exp: 5000000, expected: 100},
+		{rate: "medium-slow", exp: 8, expected: 1},
+		{rate: "medium-slow", exp: 9, expected: 2},
+	}
+	for _, c := range cases {
+		actual, err := Level(c.rate, c.exp)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if actual != c.expected {
+			t.Errorf("Level(%v, %v): expected: %v, but got %v.", c.rate, c.exp, c.expected, actual)
+		}
+	}
+}
+
+func TestCurvesOnlyGoUp(t *testing.T) {
+	for rate := range curves {
+		previous := -1
+		for level := 1; level <= MaxLevel; level++ {
+			exp, _ := Experience(rate, level)
+			if exp <= previous {
+				t.Errorf("%v: level %v needs %v experience, level %v needed %v", rate, level, exp, level-1, previous)
+			}
+			previous = exp
+		}
+	}
+}
+
+func TestYield(t *testing.T) {
+	if actual := Yield(112, 5); actual != 80 {
+		t.Errorf("Expected: %v, but got %v.", 80, actual)
+	}
+	if actual := Yield(1, 1); actual != 1 {
+		t.Errorf("expected at least 1 experience, got %v", actual)
+	}
+}
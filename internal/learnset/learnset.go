@@ -0,0 +1,49 @@
+// Package learnset works out which moves a pokemon learns by leveling up in
+// a given version group.
+package learnset
+
+import (
+	"sort"
+
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+// DefaultVersionGroup is the games whose learnsets are used when none is
+// picked, the ones the location areas PokeAPI lists first come from
+const DefaultVersionGroup = "diamond-pearl"
+
+// Move is a move and the level it's learned at
+type Move struct {
+	Name  string
+	Level int
+}
+
+// LevelUp lists the moves pokemon learns by leveling up in versionGroup,
+// ordered by level
+func LevelUp(pokemon pokeapi.Pokemon, versionGroup string) []Move {
+	moves := []Move{}
+	for _, move := range pokemon.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if detail.VersionGroup.Name != versionGroup || detail.MoveLearnMethod.Name != "level-up" {
+				continue
+			}
+			moves = append(moves, Move{Name: move.Move.Name, Level: detail.LevelLearnedAt})
+		}
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		return moves[i].Level < moves[j].Level
+	})
+	return moves
+}
+
+// Between returns the moves learned after level from, up to and including
+// level to, in the order they're learned
+func Between(moves []Move, from, to int) []string {
+	names := []string{}
+	for _, move := range moves {
+		if move.Level > from && move.Level <= to {
+			names = append(names, move.Name)
+		}
+	}
+	return names
+}
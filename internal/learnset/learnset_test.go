@@ -0,0 +1,58 @@
+package learnset
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+const pikachuMoves = `{"moves": [
+	{"move": {"name": "quick-attack"}, "version_group_details": [
+		{"level_learned_at": 13, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "diamond-pearl"}},
+		{"level_learned_at": 16, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "red-blue"}}
+	]},
+	{"move": {"name": "thunder-shock"}, "version_group_details": [
+		{"level_learned_at": 1, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "diamond-pearl"}}
+	]},
+	{"move": {"name": "thunderbolt"}, "version_group_details": [
+		{"level_learned_at": 0, "move_learn_method": {"name": "machine"}, "version_group": {"name": "diamond-pearl"}}
+	]},
+	{"move": {"name": "tail-whip"}, "version_group_details": [
+		{"level_learned_at": 6, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "diamond-pearl"}}
+	]}
+]}`
+
+func TestLevelUp(t *testing.T) {
+	pikachu := pokeapi.Pokemon{}
+	if err := json.Unmarshal([]byte(pikachuMoves), &pikachu); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	moves := LevelUp(pikachu, "diamond-pearl")
+	expected := []Move{{Name: "thunder-shock", Level: 1}, {Name: "tail-whip", Level: 6}, {Name: "quick-attack", Level: 13}}
+	if !reflect.DeepEqual(moves, expected) {
+		t.Errorf("Expected: %v, but got %v.", expected, moves)
+	}
+
+	cases := []struct {
+		from     int
+		to       int
+		expected []string
+	}{
+		{from: 0, to: 5, expected: []string{"thunder-shock"}},
+		{from: 5, to: 6, expected: []string{"tail-whip"}},
+		{from: 6, to: 12, expected: []string{}},
+		{from: 1, to: 100, expected: []string{"tail-whip", "quick-attack"}},
+	}
+	for _, c := range cases {
+		if actual := Between(moves, c.from, c.to); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Between(%v, %v): expected: %v, but got %v.", c.from, c.to, c.expected, actual)
+		}
+	}
+
+	if moves := LevelUp(pikachu, "x-y"); len(moves) != 0 {
+		t.Errorf("expected no moves for another version group, got %v", moves)
+	}
+}
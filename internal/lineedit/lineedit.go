@@ -0,0 +1,525 @@
+// Package lineedit is a small readline: it puts the terminal in raw mode and
+// gives the REPL cursor movement, history (persisted to a file), reverse
+// search with ctrl-r and tab completion. when input isn't a terminal it just
+// reads plain lines.
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ErrInterrupted is returned by ReadLine when the user presses ctrl-c
+var ErrInterrupted = errors.New("lineedit: interrupted")
+
+// DefaultMaxHistory is how many lines of history are kept unless told otherwise
+const DefaultMaxHistory = 1000
+
+// Completer returns the candidates for the word being typed. before is the
+// line up to the cursor, the editor completes its last word and drops the
+// candidates that don't start with it.
+type Completer func(before string) []string
+
+// Editor reads lines from a terminal
+type Editor struct {
+	in          *bufio.Reader
+	fd          int // -1 when in is not a terminal
+	out         io.Writer
+	history     []string
+	historyPath string
+	width       func() int // columns in the terminal, 0 when unknown
+
+	// Complete is called on tab, nil disables completion
+	Complete Completer
+	// MaxHistory caps the history kept in memory and on disk
+	MaxHistory int
+}
+
+// New builds an editor reading from in and echoing to out. raw editing is
+// only used when in is a terminal.
+func New(in io.Reader, out io.Writer) *Editor {
+	e := &Editor{
+		in:         bufio.NewReader(in),
+		fd:         -1,
+		out:        out,
+		MaxHistory: DefaultMaxHistory,
+	}
+	e.width = func() int { return 0 }
+	if f, ok := in.(*os.File); ok && isTerminal(int(f.Fd())) {
+		e.fd = int(f.Fd())
+		// asked on every redraw so resizing the window is picked up
+		e.width = func() int { return termWidth(e.fd) }
+	}
+	return e
+}
+
+// Interactive reports whether the editor is reading from a terminal
+func (e *Editor) Interactive() bool {
+	return e.fd >= 0
+}
+
+// LoadHistory reads the history at path and remembers path so new lines get
+// appended to it. a missing file is fine, it gets created on the first line.
+func (e *Editor) LoadHistory(path string) error {
+	e.historyPath = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			e.history = append(e.history, line)
+		}
+	}
+	if len(e.history) > e.MaxHistory {
+		// the file only ever grows, trim it back down while we're here
+		e.history = e.history[len(e.history)-e.MaxHistory:]
+		return os.WriteFile(path, []byte(strings.Join(e.history, "\n")+"\n"), 0o644)
+	}
+	return nil
+}
+
+// History returns the remembered lines, oldest first
+func (e *Editor) History() []string {
+	return append([]string(nil), e.history...)
+}
+
+// AddHistory remembers line, skipping blanks and repeats of the last line
+func (e *Editor) AddHistory(line string) error {
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+	if len(e.history) > 0 && e.history[len(e.history)-1] == line {
+		return nil
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > e.MaxHistory {
+		e.history = e.history[len(e.history)-e.MaxHistory:]
+	}
+	if e.historyPath == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(e.historyPath), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(e.historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadLine prints prompt and returns the next line without its newline.
+// it returns io.EOF at the end of input (or ctrl-d on an empty line) and
+// ErrInterrupted on ctrl-c. lines typed at a terminal go into the history.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	if !e.Interactive() {
+		return e.readPlain(prompt)
+	}
+
+	restore, err := makeRaw(e.fd)
+	if err != nil {
+		return e.readPlain(prompt)
+	}
+	line, err := e.edit(prompt)
+	restore()
+	fmt.Fprint(e.out, "\n")
+	if err != nil {
+		return "", err
+	}
+	if err := e.AddHistory(line); err != nil {
+		return line, fmt.Errorf("saving history: %w", err)
+	}
+	return line, nil
+}
+
+func (e *Editor) readPlain(prompt string) (string, error) {
+	fmt.Fprint(e.out, prompt)
+	line, err := e.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		// last line without a trailing newline still counts
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// keys that arrive as escape sequences, kept out of the range of real runes
+const (
+	keyUp rune = -(iota + 1)
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyDelete
+	keyUnknown
+)
+
+const (
+	ctrlA     = 1
+	ctrlB     = 2
+	ctrlC     = 3
+	ctrlD     = 4
+	ctrlE     = 5
+	ctrlF     = 6
+	ctrlG     = 7
+	ctrlH     = 8
+	tab       = 9
+	lineFeed  = 10
+	ctrlK     = 11
+	ctrlL     = 12
+	enter     = 13
+	ctrlN     = 14
+	ctrlP     = 16
+	ctrlR     = 18
+	ctrlU     = 21
+	ctrlW     = 23
+	escape    = 27
+	backspace = 127
+)
+
+// readKey reads one key press, folding escape sequences into the key consts
+func (e *Editor) readKey() (rune, error) {
+	r, _, err := e.in.ReadRune()
+	if err != nil || r != escape {
+		return r, err
+	}
+
+	// the terminal sends a whole sequence at once. an escape with nothing
+	// after it is the escape key on its own, waiting for more would hang.
+	if e.in.Buffered() == 0 {
+		return escape, nil
+	}
+	next, _, err := e.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if next != '[' && next != 'O' {
+		// escape then an ordinary key, which is left to be read next
+		e.in.UnreadRune()
+		return escape, nil
+	}
+	// read the parameters up to the final byte of the sequence
+	params := []rune{}
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		if r >= 0x40 && r <= 0x7e {
+			switch {
+			case r == 'A':
+				return keyUp, nil
+			case r == 'B':
+				return keyDown, nil
+			case r == 'C':
+				return keyRight, nil
+			case r == 'D':
+				return keyLeft, nil
+			case r == 'H':
+				return keyHome, nil
+			case r == 'F':
+				return keyEnd, nil
+			case r == '~' && string(params) == "3":
+				return keyDelete, nil
+			case r == '~' && (string(params) == "1" || string(params) == "7"):
+				return keyHome, nil
+			case r == '~' && (string(params) == "4" || string(params) == "8"):
+				return keyEnd, nil
+			}
+			return keyUnknown, nil
+		}
+		params = append(params, r)
+	}
+}
+
+// lineState is the line being edited
+type lineState struct {
+	prompt string
+	buf    []rune
+	pos    int // cursor position in buf
+	row    int // how many rows the cursor is below the start of the prompt
+}
+
+// refresh redraws the prompt and the line and puts the cursor back. it
+// counts display columns rather than runes, wide runes take two, and
+// follows a long line over the rows it wraps onto.
+func (e *Editor) refresh(s *lineState) {
+	if s.row > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dA", s.row)
+	}
+	fmt.Fprintf(e.out, "\r%v%v\x1b[J", s.prompt, string(s.buf))
+	end := stringWidth(s.prompt) + stringWidth(string(s.buf))
+	cursor := stringWidth(s.prompt) + stringWidth(string(s.buf[:s.pos]))
+
+	cols := e.width()
+	if cols <= 0 {
+		// no idea how wide the terminal is, all we can do is assume it's one row
+		if back := end - cursor; back > 0 {
+			fmt.Fprintf(e.out, "\x1b[%dD", back)
+		}
+		return
+	}
+	if end > 0 && end%cols == 0 {
+		// a full last row leaves the cursor hanging at its end, move it down
+		fmt.Fprint(e.out, "\r\n")
+	}
+	row, col := cursor/cols, cursor%cols
+	if up := end/cols - row; up > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dA", up)
+	}
+	fmt.Fprint(e.out, "\r")
+	if col > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dC", col)
+	}
+	s.row = row
+}
+
+func (s *lineState) insert(text []rune) {
+	buf := append([]rune{}, s.buf[:s.pos]...)
+	buf = append(buf, text...)
+	s.buf = append(buf, s.buf[s.pos:]...)
+	s.pos += len(text)
+}
+
+func (s *lineState) set(line string) {
+	s.buf = []rune(line)
+	s.pos = len(s.buf)
+}
+
+// edit runs the key loop until enter, ctrl-c or ctrl-d
+func (e *Editor) edit(prompt string) (string, error) {
+	s := &lineState{prompt: prompt}
+	histIdx := len(e.history)
+	saved := "" // what was typed before browsing the history
+	e.refresh(s)
+
+	for {
+		key, err := e.readKey()
+		if err != nil {
+			return "", err
+		}
+		if key == ctrlR {
+			line, accepted, pending, err := e.search(s)
+			if err != nil || accepted {
+				return line, err
+			}
+			s.prompt = prompt
+			s.set(line)
+			e.refresh(s)
+			if pending == 0 {
+				continue
+			}
+			key = pending
+		}
+
+		switch key {
+		case enter, lineFeed:
+			// leave the cursor after a wrapped line, not in the middle of it
+			s.pos = len(s.buf)
+			e.refresh(s)
+			return string(s.buf), nil
+		case ctrlC:
+			return "", ErrInterrupted
+		case ctrlD:
+			if len(s.buf) == 0 {
+				return "", io.EOF
+			}
+			if s.pos < len(s.buf) {
+				s.buf = append(s.buf[:s.pos], s.buf[s.pos+1:]...)
+			}
+		case keyDelete:
+			if s.pos < len(s.buf) {
+				s.buf = append(s.buf[:s.pos], s.buf[s.pos+1:]...)
+			}
+		case backspace, ctrlH:
+			if s.pos > 0 {
+				s.buf = append(s.buf[:s.pos-1], s.buf[s.pos:]...)
+				s.pos--
+			}
+		case ctrlA, keyHome:
+			s.pos = 0
+		case ctrlE, keyEnd:
+			s.pos = len(s.buf)
+		case ctrlB, keyLeft:
+			if s.pos > 0 {
+				s.pos--
+			}
+		case ctrlF, keyRight:
+			if s.pos < len(s.buf) {
+				s.pos++
+			}
+		case ctrlK:
+			s.buf = s.buf[:s.pos]
+		case ctrlU:
+			s.buf = s.buf[s.pos:]
+			s.pos = 0
+		case ctrlW:
+			start := s.pos
+			for start > 0 && unicode.IsSpace(s.buf[start-1]) {
+				start--
+			}
+			for start > 0 && !unicode.IsSpace(s.buf[start-1]) {
+				start--
+			}
+			s.buf = append(s.buf[:start], s.buf[s.pos:]...)
+			s.pos = start
+		case ctrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+			s.row = 0
+		case ctrlP, keyUp:
+			if histIdx > 0 {
+				if histIdx == len(e.history) {
+					saved = string(s.buf)
+				}
+				histIdx--
+				s.set(e.history[histIdx])
+			}
+		case ctrlN, keyDown:
+			if histIdx < len(e.history) {
+				histIdx++
+				if histIdx == len(e.history) {
+					s.set(saved)
+				} else {
+					s.set(e.history[histIdx])
+				}
+			}
+		case tab:
+			e.complete(s)
+		default:
+			if key >= ' ' && key != backspace {
+				s.insert([]rune{key})
+			}
+		}
+		e.refresh(s)
+	}
+}
+
+// search is the ctrl-r reverse incremental search. it returns the line found
+// and whether enter accepted it outright. any other key that ends the search
+// is handed back as pending so the caller can act on it.
+func (e *Editor) search(s *lineState) (line string, accepted bool, pending rune, err error) {
+	original := string(s.buf)
+	query := []rune{}
+	match := len(e.history) // index of the current match, len(history) for none
+
+	find := func(from int) {
+		for i := min(from, len(e.history)-1); i >= 0; i-- {
+			if strings.Contains(e.history[i], string(query)) {
+				match = i
+				return
+			}
+		}
+	}
+	current := func() string {
+		if match < len(e.history) {
+			return e.history[match]
+		}
+		return ""
+	}
+
+	for {
+		s.prompt = fmt.Sprintf("(reverse-i-search)`%v': ", string(query))
+		s.set(current())
+		e.refresh(s)
+		key, err := e.readKey()
+		if err != nil {
+			return "", false, 0, err
+		}
+		switch {
+		case key == ctrlR:
+			if match > 0 {
+				find(match - 1)
+			}
+		case key == backspace || key == ctrlH:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				match = len(e.history)
+				find(len(e.history) - 1)
+			}
+		case key == ctrlG || key == ctrlC:
+			return original, false, 0, nil
+		case key == enter || key == lineFeed:
+			return current(), true, 0, nil
+		case key >= ' ':
+			query = append(query, key)
+			if match == len(e.history) || !strings.Contains(current(), string(query)) {
+				find(match)
+			}
+		default:
+			if match == len(e.history) {
+				return original, false, key, nil
+			}
+			return current(), false, key, nil
+		}
+	}
+}
+
+// complete fills in the word before the cursor. one candidate is completed
+// in full, several are completed up to their common prefix and listed when
+// that doesn't get any further.
+func (e *Editor) complete(s *lineState) {
+	if e.Complete == nil {
+		return
+	}
+	before := string(s.buf[:s.pos])
+	start := s.pos
+	for start > 0 && !unicode.IsSpace(s.buf[start-1]) {
+		start--
+	}
+	partial := string(s.buf[start:s.pos])
+
+	seen := make(map[string]bool)
+	candidates := []string{}
+	for _, c := range e.Complete(before) {
+		if strings.HasPrefix(c, partial) && !seen[c] {
+			seen[c] = true
+			candidates = append(candidates, c)
+		}
+	}
+	sort.Strings(candidates)
+
+	switch len(candidates) {
+	case 0:
+		fmt.Fprint(e.out, "\a")
+	case 1:
+		s.insert([]rune(strings.TrimPrefix(candidates[0], partial) + " "))
+	default:
+		prefix := commonPrefix(candidates)
+		if len(prefix) > len(partial) {
+			s.insert([]rune(strings.TrimPrefix(prefix, partial)))
+			return
+		}
+		fmt.Fprintf(e.out, "\n%v\n", strings.Join(candidates, "  "))
+		// the line gets drawn again below the list
+		s.row = 0
+	}
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
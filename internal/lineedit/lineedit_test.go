@@ -0,0 +1,198 @@
+package lineedit
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func newTestEditor(keys string, history ...string) *Editor {
+	e := New(strings.NewReader(keys), &bytes.Buffer{})
+	e.history = history
+	return e
+}
+
+func TestEditKeys(t *testing.T) {
+	cases := []struct {
+		name     string
+		keys     string
+		history  []string
+		expected string
+	}{
+		{name: "plain", keys: "map\r", expected: "map"},
+		{name: "backspace", keys: "mapp\x7f\r", expected: "map"},
+		{name: "cursor movement", keys: "atch\x1b[D\x1b[D\x1b[D\x1b[Dc\r", expected: "catch"},
+		{name: "home and end", keys: "atch\x01c\x05 pikachu\r", expected: "catch pikachu"},
+		{name: "delete word", keys: "catch ivysaur\x17pikachu\r", expected: "catch pikachu"},
+		{name: "kill to start", keys: "oops\x15map\r", expected: "map"},
+		{name: "history up", keys: "\x1b[A\x1b[A\r", history: []string{"map", "explore x"}, expected: "map"},
+		{name: "history down restores", keys: "ca\x1b[A\x1b[B\r", history: []string{"map"}, expected: "ca"},
+		{name: "reverse search", keys: "\x12exp\r", history: []string{"explore a", "map", "explore b"}, expected: "explore b"},
+		{name: "reverse search again", keys: "\x12exp\x12\r", history: []string{"explore a", "map", "explore b"}, expected: "explore a"},
+		{name: "reverse search then edit", keys: "\x12ma\x05 x\r", history: []string{"map"}, expected: "map x"},
+		{name: "reverse search cancel", keys: "cat\x12ma\x07ch\r", history: []string{"map"}, expected: "catch"},
+		{name: "escape then a key", keys: "cat\x1bch\r", expected: "catch"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := newTestEditor(c.keys, c.history...)
+			actual, err := e.edit("> ")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != c.expected {
+				t.Errorf("Expected: %q, but got: %q.", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLoneEscape(t *testing.T) {
+	// one byte per read, like someone pressing escape and nothing else yet
+	e := New(iotest.OneByteReader(strings.NewReader("\x1b[A")), io.Discard)
+	for _, expected := range []rune{escape, '[', 'A'} {
+		key, err := e.readKey()
+		if err != nil || key != expected {
+			t.Errorf("Expected: %q, but got: %q (%v).", expected, key, err)
+		}
+	}
+}
+
+func TestRefreshWidths(t *testing.T) {
+	cases := []struct {
+		name     string
+		buf      string
+		pos      int
+		cols     int
+		expected string
+		row      int
+	}{
+		{name: "unknown width", buf: "map", pos: 1, expected: "\r> map\x1b[J\x1b[2D"},
+		{name: "wide runes", buf: "ピカチュウ", pos: 2, expected: "\r> ピカチュウ\x1b[J\x1b[6D"},
+		{name: "combining marks", buf: "pok\u00e9mon e\u0301", pos: 10, expected: "\r> pok\u00e9mon e\u0301\x1b[J"},
+		{name: "one row", buf: "map", pos: 1, cols: 80, expected: "\r> map\x1b[J\r\x1b[3C"},
+		{name: "wrapped", buf: "catch pikachu", pos: 3, cols: 10, expected: "\r> catch pikachu\x1b[J\x1b[1A\r\x1b[5C"},
+		{name: "cursor on the second row", buf: "catch pikachu", pos: 13, cols: 10, expected: "\r> catch pikachu\x1b[J\r\x1b[5C", row: 1},
+		{name: "full row", buf: "catch pi", pos: 8, cols: 10, expected: "\r> catch pi\x1b[J\r\n\r", row: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			e := New(strings.NewReader(""), out)
+			e.width = func() int { return c.cols }
+			s := &lineState{prompt: "> ", buf: []rune(c.buf), pos: c.pos}
+			e.refresh(s)
+			if out.String() != c.expected || s.row != c.row {
+				t.Errorf("Expected: %q on row %v, but got: %q on row %v.", c.expected, c.row, out.String(), s.row)
+			}
+		})
+	}
+
+	// the next redraw starts from the row the prompt is on
+	out := &bytes.Buffer{}
+	e := New(strings.NewReader(""), out)
+	e.width = func() int { return 10 }
+	s := &lineState{prompt: "> ", buf: []rune("catch pikachu"), pos: 13, row: 1}
+	e.refresh(s)
+	if !strings.HasPrefix(out.String(), "\x1b[1A\r> ") {
+		t.Errorf("expected to move up to the prompt first, got %q", out.String())
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	cases := map[string]int{
+		"pikachu":       7,
+		"ピカチュウ":         10,
+		"e\u0301":       1,
+		"It's shiny! ✨": 14,
+		"":              0,
+	}
+	for s, expected := range cases {
+		if actual := stringWidth(s); actual != expected {
+			t.Errorf("%q: Expected: %v, but got: %v.", s, expected, actual)
+		}
+	}
+}
+
+func TestEditInterruptAndEOF(t *testing.T) {
+	if _, err := newTestEditor("cat\x03").edit("> "); !errors.Is(err, ErrInterrupted) {
+		t.Errorf("expected ErrInterrupted, got %v", err)
+	}
+	if _, err := newTestEditor("\x04").edit("> "); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	complete := func(before string) []string {
+		if !strings.Contains(before, " ") {
+			return []string{"catch", "map", "mapb", "explore"}
+		}
+		return []string{"ivysaur", "venusaur"}
+	}
+	cases := []struct {
+		keys     string
+		expected string
+	}{
+		{keys: "ca\t\r", expected: "catch "},
+		{keys: "ma\t\r", expected: "map"},
+		{keys: "catch iv\t\r", expected: "catch ivysaur "},
+		{keys: "x\t\r", expected: "x"},
+	}
+	for _, c := range cases {
+		e := newTestEditor(c.keys)
+		e.Complete = complete
+		actual, err := e.edit("> ")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if actual != c.expected {
+			t.Errorf("Expected: %q, but got: %q.", c.expected, actual)
+		}
+	}
+}
+
+func TestHistoryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	e := New(strings.NewReader(""), io.Discard)
+	if err := e.LoadHistory(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range []string{"map", "map", "", "catch pikachu"} {
+		if err := e.AddHistory(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	other := New(strings.NewReader(""), io.Discard)
+	other.MaxHistory = 1
+	if err := other.LoadHistory(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h := other.History(); len(h) != 1 || h[0] != "catch pikachu" {
+		t.Errorf("unexpected history: %q", h)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "catch pikachu\n" {
+		t.Errorf("expected the file to be trimmed, got %q", data)
+	}
+}
+
+func TestReadLinePlain(t *testing.T) {
+	e := New(strings.NewReader("map\ncatch pikachu"), io.Discard)
+	for _, expected := range []string{"map", "catch pikachu"} {
+		line, err := e.ReadLine("> ")
+		if err != nil || line != expected {
+			t.Errorf("Expected: %q, but got: %q (%v).", expected, line, err)
+		}
+	}
+	if _, err := e.ReadLine("> "); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build linux
+
+package lineedit
+
+import "syscall"
+
+// the ioctls that read and write the terminal settings
+const (
+	ioctlGetTermios = syscall.TCGETS
+	ioctlSetTermios = syscall.TCSETS
+)
@@ -0,0 +1,20 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package lineedit
+
+import "errors"
+
+// raw mode is only implemented for linux, macos and the bsds, everywhere
+// else the editor falls back to reading plain lines
+
+func isTerminal(fd int) bool {
+	return false
+}
+
+func termWidth(fd int) int {
+	return 0
+}
+
+func makeRaw(fd int) (func() error, error) {
+	return nil, errors.New("lineedit: raw mode is not supported on this platform")
+}
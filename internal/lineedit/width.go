@@ -0,0 +1,67 @@
+package lineedit
+
+import "unicode"
+
+// wide are the ranges of runes a terminal gives two columns: east asian wide
+// and full width characters and the emoji blocks. not the whole unicode
+// table, just what turns up in practice.
+var wide = [][2]rune{
+	{0x1100, 0x115f},   // hangul jamo
+	{0x231a, 0x231b},   // watch, hourglass
+	{0x23e9, 0x23ec},   // media buttons
+	{0x23f0, 0x23f3},   // clocks
+	{0x25fd, 0x25fe},   // small squares
+	{0x2614, 0x2615},   // umbrella, hot beverage
+	{0x26a1, 0x26a1},   // high voltage
+	{0x26aa, 0x26ab},   // circles
+	{0x26bd, 0x26be},   // balls
+	{0x2705, 0x2705},   // check mark button
+	{0x270a, 0x270b},   // fists
+	{0x2728, 0x2728},   // sparkles
+	{0x274c, 0x274c},   // cross mark
+	{0x2753, 0x2755},   // question and exclamation marks
+	{0x2795, 0x2797},   // plus, minus, divide
+	{0x2b50, 0x2b50},   // star
+	{0x2e80, 0x303e},   // cjk radicals and punctuation
+	{0x3041, 0x33ff},   // kana and cjk compatibility
+	{0x3400, 0x4dbf},   // cjk extension a
+	{0x4e00, 0x9fff},   // cjk unified ideographs
+	{0xa000, 0xa4cf},   // yi
+	{0xac00, 0xd7a3},   // hangul syllables
+	{0xf900, 0xfaff},   // cjk compatibility ideographs
+	{0xfe30, 0xfe4f},   // cjk compatibility forms
+	{0xff00, 0xff60},   // full width forms
+	{0xffe0, 0xffe6},   // full width signs
+	{0x1f300, 0x1f64f}, // symbols, pictographs and emoticons
+	{0x1f680, 0x1f6ff}, // transport and map symbols
+	{0x1f900, 0x1f9ff}, // supplemental symbols and pictographs
+	{0x20000, 0x2fffd}, // cjk extension b and on
+	{0x30000, 0x3fffd},
+}
+
+// runeWidth is how many columns r takes up in a terminal: two for wide
+// runes, none for combining marks and other runes drawn on top of the one
+// before, one for the rest
+func runeWidth(r rune) int {
+	if unicode.IsControl(r) || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	for _, span := range wide {
+		if r < span[0] {
+			break
+		}
+		if r <= span[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// stringWidth is how many columns s takes up in a terminal
+func stringWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
@@ -0,0 +1,204 @@
+package pokeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lulock/pokedex/internal/pokecache"
+)
+
+// DefaultBaseURL is the root of the public PokeAPI
+const DefaultBaseURL = "https://pokeapi.co/api/v2"
+
+// ErrNotFound is returned when the API answers with a 404
+var ErrNotFound = errors.New("pokeapi: not found")
+
+// Client fetches and decodes PokeAPI resources.
+// every request goes through the same path: check the cache, fetch, store the
+// raw bytes in the cache, then decode.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	cache      *pokecache.Cache
+}
+
+// NewClient builds a client for the API rooted at baseURL.
+// an empty baseURL means DefaultBaseURL, a nil httpClient gets a default one
+// with a timeout and a nil cache disables caching.
+func NewClient(baseURL string, httpClient *http.Client, cache *pokecache.Cache) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+		cache:      cache,
+	}
+}
+
+// BaseURL returns the root every resource URL is built from
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// ListLocationAreas fetches one page of location areas.
+// pageURL is a Next or Previous cursor from an earlier page, or empty for the
+// first page.
+func (c *Client) ListLocationAreas(pageURL string) (LocationAreas, error) {
+	if pageURL == "" {
+		pageURL = c.baseURL + "/location-area/"
+	}
+	locAreas := LocationAreas{}
+	err := c.getJSON(pageURL, &locAreas)
+	return locAreas, err
+}
+
+// GetLocationArea fetches a single location area by name or id
+func (c *Client) GetLocationArea(name string) (PokemonInArea, error) {
+	area := PokemonInArea{}
+	err := c.getJSON(c.resourceURL("location-area", name), &area)
+	return area, err
+}
+
+// GetPokemon fetches a single pokemon by name or id
+func (c *Client) GetPokemon(name string) (Pokemon, error) {
+	poke := Pokemon{}
+	err := c.getJSON(c.resourceURL("pokemon", name), &poke)
+	return poke, err
+}
+
+// GetPokemonSpecies fetches a single species by name or id
+func (c *Client) GetPokemonSpecies(name string) (PokemonSpecies, error) {
+	species := PokemonSpecies{}
+	err := c.getJSON(c.resourceURL("pokemon-species", name), &species)
+	return species, err
+}
+
+// GetItem fetches a single item by name or id
+func (c *Client) GetItem(name string) (Item, error) {
+	item := Item{}
+	err := c.getJSON(c.resourceURL("item", name), &item)
+	return item, err
+}
+
+// GetMove fetches a single move by name or id
+func (c *Client) GetMove(name string) (Move, error) {
+	move := Move{}
+	err := c.getJSON(c.resourceURL("move", name), &move)
+	return move, err
+}
+
+// GetType fetches a single type by name or id
+func (c *Client) GetType(name string) (Type, error) {
+	t := Type{}
+	err := c.getJSON(c.resourceURL("type", name), &t)
+	return t, err
+}
+
+// GetEvolutionChain fetches an evolution chain. chainURL is the
+// evolution_chain url of a species.
+func (c *Client) GetEvolutionChain(chainURL string) (EvolutionChain, error) {
+	chain := EvolutionChain{}
+	err := c.getJSON(chainURL, &chain)
+	return chain, err
+}
+
+func (c *Client) resourceURL(resource, name string) string {
+	return fmt.Sprintf("%v/%v/%v", c.baseURL, resource, url.PathEscape(name))
+}
+
+func (c *Client) getJSON(url string, v any) error {
+	data, err := c.get(url)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding %v: %w", url, err)
+	}
+	return nil
+}
+
+// get returns the raw body for url, from the cache when possible.
+// only successful responses are cached, concurrent requests for the same url
+// share one fetch and stale entries are revalidated with a conditional request.
+func (c *Client) get(url string) ([]byte, error) {
+	if c.cache == nil {
+		res, err := c.fetch(url, nil)
+		return res.Val, err
+	}
+	return c.cache.GetOrRevalidate(url, func(stale *pokecache.Entry) (pokecache.Result, error) {
+		return c.fetch(url, stale)
+	})
+}
+
+// fetch does the actual request, bypassing the cache. when stale is set the
+// request is conditional and a 304 comes back as a NotModified result.
+func (c *Client) fetch(url string, stale *pokecache.Entry) (pokecache.Result, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return pokecache.Result{}, err
+	}
+	if stale != nil {
+		if stale.Validators.ETag != "" {
+			req.Header.Set("If-None-Match", stale.Validators.ETag)
+		}
+		if stale.Validators.LastModified != "" {
+			req.Header.Set("If-Modified-Since", stale.Validators.LastModified)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return pokecache.Result{}, err
+	}
+	defer resp.Body.Close()
+
+	validators := pokecache.Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		MaxAge:       maxAge(resp.Header.Get("Cache-Control")),
+	}
+	if resp.StatusCode == http.StatusNotModified && stale != nil {
+		return pokecache.Result{Validators: validators, NotModified: true}, nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return pokecache.Result{}, fmt.Errorf("%w: %v", ErrNotFound, url)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return pokecache.Result{}, fmt.Errorf("pokeapi: unexpected status %v for %v", resp.Status, url)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return pokecache.Result{}, err
+	}
+	return pokecache.Result{Val: data, Validators: validators}, nil
+}
+
+// maxAge pulls the max-age directive out of a Cache-Control header,
+// nil when there is none. max-age=0 means the response is stale right away.
+func maxAge(cacheControl string) *time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(directive), "=")
+		if !ok || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+		if err != nil || seconds < 0 {
+			return nil
+		}
+		age := time.Duration(seconds) * time.Second
+		return &age
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package pokeapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lulock/pokedex/internal/pokecache"
+)
+
+func TestClientFetchAndCache(t *testing.T) {
+	hits := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pokemon/pikachu", func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"id": 25, "name": "pikachu", "base_experience": 112}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cache := pokecache.NewCache(time.Minute)
+	defer cache.Close()
+	client := NewClient(srv.URL, srv.Client(), cache)
+	for i := 0; i < 2; i++ {
+		poke, err := client.GetPokemon("pikachu")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if poke.ID != 25 || poke.Name != "pikachu" {
+			t.Errorf("unexpected pokemon: %v %v", poke.ID, poke.Name)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("expected 1 request to the server, got %v", hits)
+	}
+}
+
+func TestClientNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	client := NewClient(srv.URL, srv.Client(), nil)
+	_, err := client.GetLocationArea("nowhere")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestListLocationAreasFirstPage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/location-area/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count": 1, "next": null, "previous": null, "results": [{"name": "canalave-city-area", "url": ""}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, srv.Client(), nil)
+	locAreas, err := client.ListLocationAreas("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(locAreas.Results) != 1 || locAreas.Results[0].Name != "canalave-city-area" {
+		t.Errorf("unexpected results: %v", locAreas.Results)
+	}
+}
+
+func TestClientRevalidates(t *testing.T) {
+	full, conditional := 0, 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pokemon/pikachu", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", "public, max-age=0")
+		w.Write([]byte(`{"id": 25, "name": "pikachu"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cache := pokecache.NewCache(time.Millisecond)
+	defer cache.Close()
+	client := NewClient(srv.URL, srv.Client(), cache)
+
+	for i := 0; i < 3; i++ {
+		poke, err := client.GetPokemon("pikachu")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if poke.Name != "pikachu" {
+			t.Errorf("unexpected pokemon: %v", poke.Name)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	if full != 1 || conditional != 2 {
+		t.Errorf("expected 1 full and 2 conditional requests, got %v and %v", full, conditional)
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	cases := []struct {
+		header   string
+		expected time.Duration
+		present  bool
+	}{
+		{header: ""},
+		{header: "public, max-age=86400", expected: 24 * time.Hour, present: true},
+		{header: "max-age=0", expected: 0, present: true},
+		{header: "no-cache"},
+		{header: "max-age=bogus"},
+	}
+	for _, c := range cases {
+		actual := maxAge(c.header)
+		if (actual != nil) != c.present || (actual != nil && *actual != c.expected) {
+			t.Errorf("maxAge(%q): expected %v (present %v), got %v", c.header, c.expected, c.present, actual)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package pokecache
 
 import (
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -25,6 +28,7 @@ func TestAddGet(t *testing.T) {
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
 			cache := NewCache(interval)
+			defer cache.Close()
 			cache.Add(c.key, c.val)
 			val, ok := cache.Get(c.key)
 			if !ok {
@@ -44,6 +48,7 @@ func TestReapLoop(t *testing.T) {
 	const baseTime = 5 * time.Millisecond
 	const waitTime = baseTime + 5*time.Millisecond
 	cache := NewCache(baseTime)
+	defer cache.Close()
 	cache.Add("https://example.com", []byte("testdata"))
 
 	_, ok := cache.Get("https://example.com")
@@ -62,4 +67,235 @@ func TestReapLoop(t *testing.T) {
 	return
 }
 
+func TestDiskTier(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	dir := t.TempDir()
+	cache := NewCache(baseTime, WithDiskTier(dir, time.Hour))
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+
+	// wait for the memory tier to forget about it
+	time.Sleep(baseTime + 5*time.Millisecond)
+
+	val, ok := cache.Get("https://example.com")
+	if !ok {
+		t.Errorf("expected to find key on disk")
+		return
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected to find value")
+		return
+	}
+
+	// a fresh cache over the same directory sees it too, like after a restart
+	other := NewCache(time.Minute, WithDiskTier(dir, time.Hour))
+	defer other.Close()
+	if _, ok := other.Get("https://example.com"); !ok {
+		t.Errorf("expected a new cache to find key on disk")
+	}
+}
+
+func TestDiskTierExpiry(t *testing.T) {
+	cache := NewCache(time.Minute, WithDiskTier(t.TempDir(), time.Millisecond))
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+	time.Sleep(5 * time.Millisecond)
+
+	other := NewCache(time.Minute, WithDiskTier(cache.disk.dir, time.Millisecond))
+	defer other.Close()
+	if _, ok := other.Get("https://example.com"); ok {
+		t.Errorf("expected disk entry to have expired")
+	}
+}
+
+func TestDiskPromotionKeepsAge(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCache(time.Minute, WithDiskTier(dir, time.Hour))
+	defer cache.Close()
+	maxAge := 50 * time.Millisecond
+	cache.AddWithValidators("https://example.com", []byte("testdata"), Validators{MaxAge: &maxAge})
+	time.Sleep(30 * time.Millisecond)
+
+	// reading it back from disk mustn't restart the clock on the max-age
+	other := NewCache(time.Minute, WithDiskTier(dir, time.Hour))
+	defer other.Close()
+	if _, ok := other.Get("https://example.com"); !ok {
+		t.Errorf("expected to find key on disk")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := other.Get("https://example.com"); ok {
+		t.Errorf("expected the promoted entry to go stale with its original age")
+	}
+}
+
+func TestMaxEntriesLRU(t *testing.T) {
+	cache := NewCache(time.Minute, WithMaxEntries(2))
+	defer cache.Close()
+	cache.Add("a", []byte("1"))
+	cache.Add("b", []byte("2"))
+	// touch a so b becomes the least recently used
+	cache.Get("a")
+	cache.Add("c", []byte("3"))
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected to find %v", key)
+		}
+	}
+	if stats := cache.Stats(); stats.Evictions != 1 || stats.Entries != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	cache := NewCache(time.Minute, WithMaxBytes(10))
+	defer cache.Close()
+	cache.Add("a", []byte("12345"))
+	cache.Add("b", []byte("12345"))
+	cache.Add("c", []byte("123"))
+
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("expected a to be evicted")
+	}
+	if stats := cache.Stats(); stats.Bytes != 8 || stats.Evictions != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// too big to ever fit, should not wipe the cache
+	cache.Add("huge", []byte("12345678901"))
+	if _, ok := cache.Get("huge"); ok {
+		t.Errorf("expected huge not to be cached")
+	}
+	if stats := cache.Stats(); stats.Entries != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestClose(t *testing.T) {
+	cache := NewCache(time.Millisecond)
+	cache.Add("https://example.com", []byte("testdata"))
+	cache.Close()
+
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected a closed cache to miss")
+	}
+	cache.Add("https://example.com/path", []byte("moretestdata"))
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("expected a closed cache to stay empty, got %+v", stats)
+	}
+	// closing twice is fine
+	cache.Close()
+}
 
+func TestGetOrFetchCoalesces(t *testing.T) {
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetch := func() ([]byte, error) {
+		fetches.Add(1)
+		<-release
+		return []byte("testdata"), nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := cache.GetOrFetch("https://example.com", fetch)
+			if err != nil || string(val) != "testdata" {
+				t.Errorf("unexpected result: %q %v", val, err)
+			}
+		}()
+	}
+	// give every goroutine a chance to pile up behind the first fetch
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("expected 1 fetch, got %v", n)
+	}
+	if _, ok := cache.Get("https://example.com"); !ok {
+		t.Errorf("expected the fetched value to be cached")
+	}
+}
+
+func TestGetOrFetchError(t *testing.T) {
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+
+	boom := errors.New("boom")
+	_, err := cache.GetOrFetch("https://example.com", func() ([]byte, error) {
+		return nil, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("expected the fetch error, got %v", err)
+	}
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected errors not to be cached")
+	}
+}
+
+func TestRevalidateNotModified(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	cache := NewCache(baseTime)
+	defer cache.Close()
+	cache.AddWithValidators("https://example.com", []byte("testdata"), Validators{ETag: `"v1"`})
+
+	time.Sleep(baseTime + 5*time.Millisecond)
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected the entry to be stale")
+	}
+
+	var seen *Entry
+	val, err := cache.GetOrRevalidate("https://example.com", func(stale *Entry) (Result, error) {
+		seen = stale
+		return Result{NotModified: true}, nil
+	})
+	if err != nil || string(val) != "testdata" {
+		t.Errorf("unexpected result: %q %v", val, err)
+	}
+	if seen == nil || seen.Validators.ETag != `"v1"` {
+		t.Errorf("expected fetch to get the stale entry, got %+v", seen)
+	}
+	if _, ok := cache.Get("https://example.com"); !ok {
+		t.Errorf("expected a 304 to make the entry fresh again")
+	}
+}
+
+func TestMaxAgeZero(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCache(time.Minute, WithDiskTier(dir, time.Hour))
+	defer cache.Close()
+	zero := time.Duration(0)
+	cache.AddWithValidators("https://example.com", []byte("testdata"), Validators{ETag: `"v1"`, MaxAge: &zero})
+	cache.AddWithValidators("https://example.com/default", []byte("testdata"), Validators{ETag: `"v1"`})
+
+	// max-age=0 means stale straight away, in memory and on disk
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected a max-age of 0 to be stale right away")
+	}
+	if _, ok := cache.Get("https://example.com/default"); !ok {
+		t.Errorf("expected no max-age to use the cache's own duration")
+	}
+	fromDisk := NewCache(time.Minute, WithDiskTier(dir, time.Hour))
+	defer fromDisk.Close()
+	if _, ok := fromDisk.Get("https://example.com"); ok {
+		t.Errorf("expected a max-age of 0 to be stale on disk too")
+	}
+
+	revalidated := false
+	cache.GetOrRevalidate("https://example.com", func(stale *Entry) (Result, error) {
+		revalidated = stale != nil
+		return Result{NotModified: true}, nil
+	})
+	if !revalidated {
+		t.Errorf("expected a revalidation for a max-age of 0")
+	}
+}
@@ -1,80 +1,448 @@
 package pokecache
 
 import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
-//	"fmt"
 )
 
-//
+// Cache keeps raw response bytes keyed by URL.
+// entries live in memory for duration (or their max-age when the response
+// had one) and, when a disk tier is configured, on disk for the much longer
+// diskTTL. entries that carry validators are kept once they go stale so they
+// can be revalidated instead of downloaded again. the memory tier can be
+// bounded by entry count and total bytes, in which case the least recently
+// used entries are evicted first.
 type Cache struct {
-	entries map[string]cacheEntry // map of cachEntries
-	mu sync.Mutex // protect the map across goroutines
-	duration time.Duration
+	entries    map[string]*list.Element // map of cachEntries, the elements live in lru
+	lru        *list.List               // most recently used at the front
+	mu         sync.Mutex               // protect the map across goroutines
+	duration   time.Duration
+	disk       *diskTier // nil when there is no disk tier
+	maxEntries int       // 0 means unbounded
+	maxBytes   int       // 0 means unbounded
+	bytes      int
+	evictions  int
+	done       chan struct{} // closed by Close to stop the reap loop
+	closed     bool
+	inflight   map[string]*call // fetches started by GetOrFetch that haven't finished
+}
+
+// call is one in-flight GetOrFetch load that other callers can wait on
+type call struct {
+	wg  sync.WaitGroup
+	val []byte
+	err error
 }
 
 type cacheEntry struct {
-	createdAt time.Time // represents when the entry was created 
-	val []byte // represents the raw data we're caching
+	key        string
+	createdAt  time.Time // represents when the entry was created, or last revalidated
+	val        []byte    // represents the raw data we're caching
+	validators Validators
+}
+
+// Validators are what a response told us about how to check it again later
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	// MaxAge is how long the server said the value stays fresh. nil means
+	// use the cache's own duration, 0 means revalidate every time.
+	MaxAge *time.Duration `json:"max_age,omitempty"`
+}
+
+// ttl is how long a value with v stays fresh when the cache would
+// otherwise keep it for fallback
+func (v Validators) ttl(fallback time.Duration) time.Duration {
+	if v.MaxAge != nil {
+		return *v.MaxAge
+	}
+	return fallback
+}
+
+// CanRevalidate reports whether a conditional request can be made with v
+func (v Validators) CanRevalidate() bool {
+	return v.ETag != "" || v.LastModified != ""
+}
+
+// Entry is a stale value handed to a revalidating fetch
+type Entry struct {
+	Val        []byte
+	CreatedAt  time.Time
+	Validators Validators
+}
+
+// Result is what a revalidating fetch returns
+type Result struct {
+	Val        []byte
+	Validators Validators
+	// NotModified means the stale entry is still good, Val is ignored and the
+	// entry is stored again as if it had just been created
+	NotModified bool
+}
+
+// Stats is a snapshot of the memory tier
+type Stats struct {
+	Entries   int
+	Bytes     int
+	Evictions int // entries dropped to stay inside the limits, not by age
+}
+
+// Option configures optional behaviour of a Cache
+type Option func(*Cache)
+
+// WithDiskTier backs the in-memory cache with files under dir.
+// a miss in memory falls back to disk and entries on disk are good for ttl.
+func WithDiskTier(dir string, ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.disk = &diskTier{dir: dir, ttl: ttl}
+	}
+}
+
+// WithMaxEntries caps how many entries are held in memory
+func WithMaxEntries(n int) Option {
+	return func(c *Cache) {
+		c.maxEntries = n
+	}
+}
+
+// WithMaxBytes caps the total size of the values held in memory
+func WithMaxBytes(n int) Option {
+	return func(c *Cache) {
+		c.maxBytes = n
+	}
 }
 
-func NewCache(interval time.Duration) *Cache {
+func NewCache(interval time.Duration, opts ...Option) *Cache {
 	// initialise a map and mutex
 	// store interval
-	//fmt.Print("Creating new cache")
 	c := Cache{
-		entries : make(map[string]cacheEntry),
-		duration : interval,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		duration: interval,
+		done:     make(chan struct{}),
+		inflight: make(map[string]*call),
+	}
+	for _, opt := range opts {
+		opt(&c)
 	}
 	// start reap loop
-	//fmt.Print("starting reap loop")
-	go c.reapLoop()	
+	go c.reapLoop()
 	// return pointer to new cache
 	return &c
+}
 
+// Get returns the value stored for key if it is still fresh.
+// a closed cache always misses.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, fresh := c.lookup(key)
+	if entry == nil || !fresh {
+		return nil, false
+	}
+	return entry.val, true
 }
 
-func (c* Cache) Get(key string) ([]byte, bool) {
+// Add stores val for key. adding to a closed cache does nothing.
+func (c *Cache) Add(key string, val []byte) {
+	c.AddWithValidators(key, val, Validators{})
+}
+
+// AddWithValidators stores val for key along with what is needed to
+// revalidate it once it goes stale
+func (c *Cache) AddWithValidators(key string, val []byte, v Validators) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if entry, exists := c.entries[key]; exists {
-		return entry.val, true
+	if c.closed {
+		return
+	}
+	entry := c.set(cacheEntry{
+		key:        key,
+		createdAt:  time.Now(),
+		val:        val,
+		validators: v,
+	})
+	if c.disk != nil {
+		// the disk tier is best effort, a failed write just means a later miss
+		c.disk.add(entry)
 	}
+}
 
-	return nil, false
+// GetOrFetch returns the value for key, calling fetch to load it on a miss.
+// concurrent callers missing the same key share a single fetch: they all get
+// its result, errors are handed to every waiter but never cached, and a
+// successful value is added once.
+func (c *Cache) GetOrFetch(key string, fetch func() ([]byte, error)) ([]byte, error) {
+	return c.GetOrRevalidate(key, func(*Entry) (Result, error) {
+		val, err := fetch()
+		return Result{Val: val}, err
+	})
 }
 
-func (c *Cache) Add(key string, val []byte) {
+// GetOrRevalidate works like GetOrFetch but hands fetch the stale entry for
+// key when there is one it could revalidate, nil otherwise. a NotModified
+// result refreshes the stale entry instead of replacing it.
+func (c *Cache) GetOrRevalidate(key string, fetch func(stale *Entry) (Result, error)) ([]byte, error) {
+	c.mu.Lock()
+	entry, fresh := c.lookup(key)
+	if entry != nil && fresh {
+		c.mu.Unlock()
+		return entry.val, nil
+	}
+	if inflight, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		inflight.wg.Wait()
+		return inflight.val, inflight.err
+	}
+	current := &call{}
+	current.wg.Add(1)
+	c.inflight[key] = current
+	c.mu.Unlock()
+
+	var stale *Entry
+	if entry != nil && entry.validators.CanRevalidate() {
+		stale = &Entry{
+			Val:        entry.val,
+			CreatedAt:  entry.createdAt,
+			Validators: entry.validators,
+		}
+	}
+
+	res, err := fetch(stale)
+	switch {
+	case err != nil:
+		current.err = err
+	case res.NotModified && stale != nil:
+		current.val = stale.Val
+		c.AddWithValidators(key, stale.Val, mergeValidators(stale.Validators, res.Validators))
+	default:
+		current.val = res.Val
+		c.AddWithValidators(key, res.Val, res.Validators)
+	}
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	c.mu.Unlock()
+	current.wg.Done()
+	return current.val, current.err
+}
+
+// mergeValidators keeps the old validators unless a 304 sent new ones
+func mergeValidators(old, updated Validators) Validators {
+	if updated.ETag != "" {
+		old.ETag = updated.ETag
+	}
+	if updated.LastModified != "" {
+		old.LastModified = updated.LastModified
+	}
+	if updated.MaxAge != nil {
+		old.MaxAge = updated.MaxAge
+	}
+	return old
+}
+
+// Close stops the reap loop and drops everything held in memory.
+// the disk tier is left alone so it can be picked up by the next cache.
+// closing an already closed cache is a no-op.
+func (c *Cache) Close() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.entries[key] = cacheEntry {
-		createdAt: time.Now(),
-		val: val,
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.done)
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.bytes = 0
+}
+
+// Stats reports the current size of the memory tier and how many entries
+// have been evicted so far
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Entries:   len(c.entries),
+		Bytes:     c.bytes,
+		Evictions: c.evictions,
+	}
+}
+
+// lookup finds key in memory, then on disk, and reports whether it is still
+// fresh. stale entries are only returned when they can be revalidated.
+// callers hold c.mu.
+func (c *Cache) lookup(key string) (*cacheEntry, bool) {
+	if c.closed {
+		return nil, false
+	}
+	var inMemory *cacheEntry
+	if elem, exists := c.entries[key]; exists {
+		c.lru.MoveToFront(elem)
+		inMemory = elem.Value.(*cacheEntry)
+		if c.fresh(inMemory) || c.disk == nil {
+			return inMemory, c.fresh(inMemory)
+		}
+		// the memory copy went stale but the disk tier may still be good
+	}
+
+	if c.disk == nil {
+		return nil, false
+	}
+	entry, fresh, ok := c.disk.get(key)
+	if !ok {
+		return inMemory, false
+	}
+	if !fresh {
+		return &entry, false
+	}
+	// promote into memory so the next Get doesn't touch the disk. the entry
+	// keeps its age, being read back doesn't make it any fresher.
+	promoted := c.set(entry)
+	return &promoted, true
+}
+
+// fresh reports whether entry can be served without asking the server
+func (c *Cache) fresh(entry *cacheEntry) bool {
+	return time.Since(entry.createdAt) < entry.validators.ttl(c.duration)
+}
+
+// set stores entry in memory as the most recently used one and evicts from
+// the back until the cache fits its limits again. callers hold c.mu.
+func (c *Cache) set(entry cacheEntry) cacheEntry {
+	if elem, exists := c.entries[entry.key]; exists {
+		c.remove(elem)
+	}
+	if c.maxBytes > 0 && len(entry.val) > c.maxBytes {
+		// would push everything else out and still not fit, skip the memory tier
+		return entry
+	}
+
+	stored := entry
+	c.entries[entry.key] = c.lru.PushFront(&stored)
+	c.bytes += len(entry.val)
+	for c.overLimit() {
+		c.remove(c.lru.Back())
+		c.evictions++
 	}
-	//fmt.Println("Added!")
-	return
+	return entry
+}
+
+func (c *Cache) overLimit() bool {
+	if c.maxEntries > 0 && len(c.entries) > c.maxEntries {
+		return true
+	}
+	return c.maxBytes > 0 && c.bytes > c.maxBytes
+}
+
+// remove drops elem from memory. callers hold c.mu.
+func (c *Cache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= len(entry.val)
 }
 
 func (c *Cache) reapLoop() {
 	// should remove any entries that are older than the interval
 	// loop through all entries
-	//if time.now - entry.createdTime >= duration, then delete entry
+	// stale entries we can revalidate are kept, the size limits take care of them
 	ticker := time.NewTicker(c.duration)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <- ticker.C:
-			//fmt.Println("tick at", t)
+		case <-c.done:
+			return
+		case <-ticker.C:
 			c.mu.Lock()
-			for k,entry := range c.entries {
-				if time.Since(entry.createdAt) >= c.duration {
-					//fmt.Println("Delete!")
-					delete(c.entries, k)
+			for _, elem := range c.entries {
+				entry := elem.Value.(*cacheEntry)
+				if !c.fresh(entry) && !entry.validators.CanRevalidate() {
+					c.remove(elem)
 				}
 			}
 			c.mu.Unlock()
 		}
 	}
-	return
+}
+
+// diskTier stores one JSON file per key, named after the hash of the key
+type diskTier struct {
+	dir string
+	ttl time.Duration
+}
+
+type diskEntry struct {
+	Key        string     `json:"key"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Val        []byte     `json:"val"`
+	Validators Validators `json:"validators"`
+}
+
+func (d *diskTier) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get reads key from disk. entries past the ttl are reported as stale when
+// they can be revalidated and deleted otherwise.
+func (d *diskTier) get(key string) (entry cacheEntry, fresh bool, ok bool) {
+	path := d.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cacheEntry{}, false, false
+	}
+	stored := diskEntry{}
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Key != key {
+		return cacheEntry{}, false, false
+	}
+	entry = cacheEntry{
+		key:        key,
+		createdAt:  stored.CreatedAt,
+		val:        stored.Val,
+		validators: stored.Validators,
+	}
+	// the server can ask for less time than the disk tier keeps things, not more
+	if time.Since(stored.CreatedAt) < min(d.ttl, entry.validators.ttl(d.ttl)) {
+		return entry, true, true
+	}
+	if entry.validators.CanRevalidate() {
+		return entry, false, true
+	}
+	os.Remove(path)
+	return cacheEntry{}, false, false
+}
+
+func (d *diskTier) add(entry cacheEntry) error {
+	data, err := json.Marshal(diskEntry{
+		Key:        entry.key,
+		CreatedAt:  entry.createdAt,
+		Val:        entry.val,
+		Validators: entry.validators,
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(d.dir, "entry.*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(entry.key))
 }
@@ -0,0 +1,67 @@
+package save
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the trainer whose save is the base save itself, so
+// saves from before there were profiles carry on as the default trainer
+const DefaultProfile = "default"
+
+// ErrNoProfile is returned for a trainer that has no save yet
+var ErrNoProfile = errors.New("there's no trainer with that name")
+
+var profileName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,19}$`)
+
+// ProfilePath is where the named trainer's save lives. other trainers keep
+// theirs in a profiles directory next to base.
+func ProfilePath(base, name string) (string, error) {
+	if !profileName.MatchString(name) {
+		return "", fmt.Errorf("%q is not a trainer name, use up to 20 lowercase letters, digits, - and _", name)
+	}
+	if name == DefaultProfile {
+		return base, nil
+	}
+	return filepath.Join(filepath.Dir(base), "profiles", name+".json"), nil
+}
+
+// Profiles lists the trainers with a save next to base, sorted by name.
+// the default trainer is always there.
+func Profiles(base string) ([]string, error) {
+	names := []string{DefaultProfile}
+	entries, err := os.ReadDir(filepath.Join(filepath.Dir(base), "profiles"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if ok && !entry.IsDir() && profileName.MatchString(name) && name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Exists reports whether the named trainer has a save next to base
+func Exists(base, name string) (bool, error) {
+	path, err := ProfilePath(base, name)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
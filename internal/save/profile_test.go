@@ -0,0 +1,42 @@
+package save
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "save.json")
+
+	if path, err := ProfilePath(base, DefaultProfile); path != base || err != nil {
+		t.Errorf("expected the default trainer to use the base save, got %v %v", path, err)
+	}
+	for _, name := range []string{"", "Ash", "../ash", "a very long trainer name"} {
+		if _, err := ProfilePath(base, name); err == nil {
+			t.Errorf("expected an error for the trainer name %q", name)
+		}
+	}
+
+	if names, err := Profiles(base); !slices.Equal(names, []string{DefaultProfile}) || err != nil {
+		t.Errorf("expected only the default trainer, got %v %v", names, err)
+	}
+	for _, name := range []string{"misty", "brock"} {
+		path, err := ProfilePath(base, name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := Write(path, New()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if names, err := Profiles(base); !slices.Equal(names, []string{"brock", DefaultProfile, "misty"}) || err != nil {
+		t.Errorf("unexpected trainers: %v %v", names, err)
+	}
+	if ok, err := Exists(base, "misty"); !ok || err != nil {
+		t.Errorf("expected misty to exist, got %v %v", ok, err)
+	}
+	if ok, err := Exists(base, DefaultProfile); ok || err != nil {
+		t.Errorf("expected no default save yet, got %v %v", ok, err)
+	}
+}
@@ -0,0 +1,162 @@
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/lulock/pokedex/internal/bag"
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+// CurrentVersion is the save format written by this build.
+// bump it whenever File changes shape and teach Load how to upgrade.
+//
+//	1: the pokedex
+//	2: the bag
+//	3: the current location
+//	4: owned pokemon, one record per catch
+//	5: experience and moves of owned pokemon, older ones catch up on their
+//	   first level up
+//	6: the party and pc boxes
+//	7: trainer statistics, older saves count the pokemon they own as caught
+const CurrentVersion = 7
+
+// File is everything we keep between sessions
+type File struct {
+	Version int                        `json:"version"`
+	SavedAt time.Time                  `json:"saved_at"`
+	Pokedex map[string]pokeapi.Pokemon `json:"pokedex"`
+	Bag     bag.Bag                    `json:"bag"`
+	// Location is the area the trainer last explored
+	Location string `json:"location"`
+	// Owned is every pokemon the trainer caught, the pokedex only keeps
+	// the species data
+	Owned collection.Collection `json:"owned"`
+	Stats Stats                 `json:"stats"`
+}
+
+// Stats are the running totals of a trainer's adventure
+type Stats struct {
+	Caught      int `json:"caught"`
+	Escaped     int `json:"escaped"` // wild pokemon that broke free of a ball
+	BattlesWon  int `json:"battles_won"`
+	BattlesLost int `json:"battles_lost"`
+	MoneyEarned int `json:"money_earned"`
+}
+
+// New returns an empty save at the current version
+func New() File {
+	return File{
+		Version: CurrentVersion,
+		Pokedex: make(map[string]pokeapi.Pokemon),
+		Bag:     bag.New(),
+	}
+}
+
+// upgradedLevel is the level given to pokemon from saves that didn't record one
+const upgradedLevel = 5
+
+// upgrade brings a save written by an older build up to CurrentVersion
+func upgrade(f *File) {
+	if f.Version < 2 {
+		// saves from before the bag existed get the starter bag
+		f.Bag = bag.New()
+	}
+	if f.Version < 4 {
+		// older saves only knew about species, each one becomes a single
+		// owned pokemon. we don't know when or where it was caught.
+		names := make([]string, 0, len(f.Pokedex))
+		for name := range f.Pokedex {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			f.Owned.Add(collection.Owned{
+				Species:   name,
+				PokemonID: f.Pokedex[name].ID,
+				Level:     upgradedLevel,
+			})
+		}
+	}
+	if f.Version < 6 {
+		// everyone gets a place, the first six caught make up the party
+		f.Owned.Tidy()
+	}
+	if f.Version < 7 {
+		// nothing was counted before, but everything owned was caught once
+		f.Stats = Stats{Caught: len(f.Owned.Pokemon)}
+	}
+	f.Version = CurrentVersion
+}
+
+// DefaultPath is where the save lives when no path is given,
+// under the user's config dir
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pokedex", "save.json"), nil
+}
+
+// Load reads the save at path.
+// a missing file is not an error, it just means a fresh start.
+func Load(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return New(), nil
+	}
+	if err != nil {
+		return File{}, err
+	}
+
+	f := File{}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return File{}, fmt.Errorf("reading save %v: %w", path, err)
+	}
+	if f.Version > CurrentVersion {
+		return File{}, fmt.Errorf("save %v has version %v, this build only understands up to %v", path, f.Version, CurrentVersion)
+	}
+	if f.Pokedex == nil {
+		f.Pokedex = make(map[string]pokeapi.Pokemon)
+	}
+	upgrade(&f)
+	return f, nil
+}
+
+// Write stores f at path. it writes to a temp file first and renames it
+// over the old save so a crash never leaves a half written file behind.
+func Write(path string, f File) error {
+	f.Version = CurrentVersion
+	f.SavedAt = time.Now()
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,113 @@
+package save
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lulock/pokedex/internal/bag"
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+func TestWriteLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "save.json")
+
+	f := New()
+	f.Pokedex["pikachu"] = pokeapi.Pokemon{ID: 25, Name: "pikachu"}
+	if err := Write(path, f); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.Version != CurrentVersion {
+		t.Errorf("expected version %v, got %v", CurrentVersion, loaded.Version)
+	}
+	if loaded.Pokedex["pikachu"].ID != 25 {
+		t.Errorf("expected pikachu to survive the round trip, got %v", loaded.Pokedex)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	f, err := Load(filepath.Join(t.TempDir(), "nope.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Pokedex == nil || len(f.Pokedex) != 0 {
+		t.Errorf("expected an empty pokedex, got %v", f.Pokedex)
+	}
+}
+
+func TestLoadNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	os.WriteFile(path, []byte(`{"version": 999}`), 0o644)
+	if _, err := Load(path); err == nil {
+		t.Errorf("expected an error for a save from the future")
+	}
+}
+
+func TestLoadUpgradesVersion1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	os.WriteFile(path, []byte(`{"version": 1, "pokedex": {"pikachu": {"id": 25, "name": "pikachu"}}}`), 0o644)
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Version != CurrentVersion || f.Pokedex["pikachu"].ID != 25 {
+		t.Errorf("unexpected upgrade: %+v", f)
+	}
+	if f.Bag.Count(bag.DefaultBall) == 0 || f.Bag.Money != bag.StarterMoney {
+		t.Errorf("expected an old save to get the starter bag, got %+v", f.Bag)
+	}
+}
+
+func TestLoadUpgradesVersion3(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	os.WriteFile(path, []byte(`{"version": 3, "pokedex": {"pikachu": {"id": 25, "name": "pikachu"}, "psyduck": {"id": 54, "name": "psyduck"}}}`), 0o644)
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(f.Owned.Pokemon) != 2 {
+		t.Fatalf("expected every species to become an owned pokemon, got %+v", f.Owned)
+	}
+	pikachu := f.Owned.Pokemon[0]
+	if pikachu.ID != 1 || pikachu.Species != "pikachu" || pikachu.PokemonID != 25 || pikachu.Level != upgradedLevel {
+		t.Errorf("unexpected upgraded pokemon: %+v", pikachu)
+	}
+	if next, _ := f.Owned.Add(collection.Owned{Species: "magikarp"}); next.ID != 3 {
+		t.Errorf("expected new catches to continue after the upgraded ones, got id %v", next.ID)
+	}
+}
+
+func TestLoadUpgradesVersion5(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	pokemon := []string{}
+	for id := 1; id <= 8; id++ {
+		pokemon = append(pokemon, fmt.Sprintf(`{"id": %v, "species": "magikarp", "level": 5}`, id))
+	}
+	os.WriteFile(path, []byte(`{"version": 5, "owned": {"next_id": 9, "pokemon": [`+strings.Join(pokemon, ",")+`]}}`), 0o644)
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the first six caught make up the party, the rest go to the pc
+	if !slices.Equal(f.Owned.Party, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("unexpected party: %v", f.Owned.Party)
+	}
+	if len(f.Owned.Boxes) != 1 || !slices.Equal(f.Owned.Boxes[0], []int{7, 8}) {
+		t.Errorf("unexpected boxes: %v", f.Owned.Boxes)
+	}
+	if f.Stats.Caught != 8 {
+		t.Errorf("expected the owned pokemon to count as caught, got %+v", f.Stats)
+	}
+}
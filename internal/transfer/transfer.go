@@ -0,0 +1,103 @@
+// Package transfer reads and writes the files owned pokemon travel in
+// between saves. a file carries the pokemon, its species data so the new
+// trainer's pokedex doesn't need the api, and a checksum over both.
+//
+// the checksum is a plain sha256 that anyone can recompute, so it only
+// catches files that were damaged or edited by accident. it doesn't stop
+// someone who means to tamper with a file.
+package transfer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+// Format names what a transfer file is, so other json isn't mistaken for one
+const Format = "pokedex-pokemon"
+
+// CurrentVersion is the transfer format written by this build.
+// bump it whenever File changes shape and teach Decode how to read the old one.
+//
+//	1: one owned pokemon and its species
+const CurrentVersion = 1
+
+// ErrChecksum is returned for a file that was changed after it was exported,
+// unless whoever changed it recomputed the checksum too
+var ErrChecksum = errors.New("the checksum doesn't match, the file was changed after it was exported")
+
+// File is one exported pokemon
+type File struct {
+	Format     string           `json:"format"`
+	Version    int              `json:"version"`
+	ExportedAt time.Time        `json:"exported_at"`
+	Trainer    string           `json:"trainer,omitempty"` // who exported it
+	Pokemon    collection.Owned `json:"pokemon"`
+	Species    pokeapi.Pokemon  `json:"species"`
+	// Checksum is the sha256 of the file with an empty checksum, in hex
+	Checksum string `json:"checksum"`
+}
+
+// New returns a file at the current version holding pokemon
+func New(trainer string, pokemon collection.Owned, species pokeapi.Pokemon) File {
+	return File{
+		Format:     Format,
+		Version:    CurrentVersion,
+		ExportedAt: time.Now().UTC(),
+		Trainer:    trainer,
+		Pokemon:    pokemon,
+		Species:    species,
+	}
+}
+
+func checksum(f File) (string, error) {
+	f.Checksum = ""
+	data, err := json.Marshal(f)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Encode stamps f with its checksum and returns it ready to be written
+func Encode(f File) ([]byte, error) {
+	sum, err := checksum(f)
+	if err != nil {
+		return nil, err
+	}
+	f.Checksum = sum
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// Decode reads a transfer file, refusing anything that isn't one, was
+// written by a newer build or doesn't match its checksum
+func Decode(data []byte) (File, error) {
+	f := File{}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return File{}, fmt.Errorf("not a pokemon transfer file: %w", err)
+	}
+	if f.Format != Format {
+		return File{}, fmt.Errorf("not a pokemon transfer file, the format is %q", f.Format)
+	}
+	if f.Version < 1 || f.Version > CurrentVersion {
+		return File{}, fmt.Errorf("transfer file has version %v, this build only understands up to %v", f.Version, CurrentVersion)
+	}
+	sum, err := checksum(f)
+	if err != nil {
+		return File{}, err
+	}
+	if sum != f.Checksum {
+		return File{}, ErrChecksum
+	}
+	if f.Pokemon.Species == "" || f.Pokemon.Species != f.Species.Name {
+		return File{}, fmt.Errorf("transfer file holds a %q but species data for %q", f.Pokemon.Species, f.Species.Name)
+	}
+	return f, nil
+}
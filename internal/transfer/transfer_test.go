@@ -0,0 +1,47 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	pokemon := collection.Owned{ID: 3, Species: "pikachu", PokemonID: 25, Nickname: "Sparky", Level: 12, Moves: []string{"thunder-shock"}, CaughtAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+	data, err := Encode(New("ash", pokemon, pokeapi.Pokemon{ID: 25, Name: "pikachu"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f, err := Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Trainer != "ash" || f.Pokemon.Nickname != "Sparky" || f.Pokemon.Level != 12 || f.Species.ID != 25 {
+		t.Errorf("unexpected file: %+v", f)
+	}
+
+	// any edit breaks the checksum
+	edited := bytes.Replace(data, []byte(`"level": 12`), []byte(`"level": 100`), 1)
+	if _, err := Decode(edited); !errors.Is(err, ErrChecksum) {
+		t.Errorf("expected ErrChecksum, got %v", err)
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	newer, _ := Encode(File{Format: Format, Version: CurrentVersion + 1})
+	cases := map[string][]byte{
+		"not json":      []byte("pikachu"),
+		"other json":    []byte(`{"version": 1}`),
+		"newer version": newer,
+	}
+	for name, data := range cases {
+		if _, err := Decode(data); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Package typechart holds type matchups built from the damage relations of
+// the type endpoint, for anything that needs to know what's super effective.
+package typechart
+
+import (
+	"sort"
+
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+// Chart holds the damage multiplier of attacking types against defending
+// types. pairs the chart doesn't know about are neutral.
+// the zero value isn't usable, build one with New.
+type Chart struct {
+	multipliers map[string]map[string]float64 // attacking -> defending -> multiplier
+	loaded      map[string]bool               // types added with Add
+}
+
+// Matchup is the multiplier of one type against a pokemon or another type
+type Matchup struct {
+	Type       string
+	Multiplier float64
+}
+
+// New returns an empty chart
+func New() *Chart {
+	return &Chart{
+		multipliers: make(map[string]map[string]float64),
+		loaded:      make(map[string]bool),
+	}
+}
+
+// Set records that attacking does multiplier damage to defending
+func (c *Chart) Set(attacking, defending string, multiplier float64) {
+	if c.multipliers[attacking] == nil {
+		c.multipliers[attacking] = make(map[string]float64)
+	}
+	c.multipliers[attacking][defending] = multiplier
+}
+
+// Add fills in the chart from the damage relations of t, both for t
+// attacking and for t defending
+func (c *Chart) Add(t pokeapi.Type) {
+	relations := t.DamageRelations
+	for _, other := range relations.DoubleDamageTo {
+		c.Set(t.Name, other.Name, 2)
+	}
+	for _, other := range relations.HalfDamageTo {
+		c.Set(t.Name, other.Name, 0.5)
+	}
+	for _, other := range relations.NoDamageTo {
+		c.Set(t.Name, other.Name, 0)
+	}
+	for _, other := range relations.DoubleDamageFrom {
+		c.Set(other.Name, t.Name, 2)
+	}
+	for _, other := range relations.HalfDamageFrom {
+		c.Set(other.Name, t.Name, 0.5)
+	}
+	for _, other := range relations.NoDamageFrom {
+		c.Set(other.Name, t.Name, 0)
+	}
+	c.loaded[t.Name] = true
+}
+
+// Has reports whether t was added to the chart
+func (c *Chart) Has(t string) bool {
+	return c.loaded[t]
+}
+
+// Effectiveness is the combined multiplier of a move of the attacking type
+// against a pokemon with the defending types. moves without a type are
+// always neutral.
+func (c *Chart) Effectiveness(attacking string, defending []string) float64 {
+	multiplier := 1.0
+	for _, defender := range defending {
+		if m, ok := c.multipliers[attacking][defender]; ok {
+			multiplier *= m
+		}
+	}
+	return multiplier
+}
+
+// Defense lists every attacking type that isn't neutral against a pokemon
+// with the defending types, strongest first. it's only complete when the
+// defending types have been added.
+func (c *Chart) Defense(defending []string) []Matchup {
+	matchups := []Matchup{}
+	for attacking := range c.multipliers {
+		if m := c.Effectiveness(attacking, defending); m != 1 {
+			matchups = append(matchups, Matchup{Type: attacking, Multiplier: m})
+		}
+	}
+	sort.Slice(matchups, func(i, j int) bool {
+		if matchups[i].Multiplier != matchups[j].Multiplier {
+			return matchups[i].Multiplier > matchups[j].Multiplier
+		}
+		return matchups[i].Type < matchups[j].Type
+	})
+	return matchups
+}
+
+// Offense is the multiplier of each attacking type against a pokemon with
+// the defending types, in the order given
+func (c *Chart) Offense(attacking []string, defending []string) []Matchup {
+	matchups := []Matchup{}
+	for _, t := range attacking {
+		matchups = append(matchups, Matchup{Type: t, Multiplier: c.Effectiveness(t, defending)})
+	}
+	return matchups
+}
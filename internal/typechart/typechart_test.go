@@ -0,0 +1,76 @@
+package typechart
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+func decode(t *testing.T, body string) pokeapi.Type {
+	t.Helper()
+	typ := pokeapi.Type{}
+	if err := json.Unmarshal([]byte(body), &typ); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return typ
+}
+
+// water and flying as the type endpoint has them, trimmed to a few types
+const water = `{"name": "water", "damage_relations": {
+	"double_damage_to": [{"name": "fire"}, {"name": "ground"}, {"name": "rock"}],
+	"half_damage_to": [{"name": "water"}, {"name": "grass"}, {"name": "dragon"}],
+	"no_damage_to": [],
+	"double_damage_from": [{"name": "grass"}, {"name": "electric"}],
+	"half_damage_from": [{"name": "fire"}, {"name": "water"}, {"name": "ice"}, {"name": "steel"}],
+	"no_damage_from": []
+}}`
+
+const flying = `{"name": "flying", "damage_relations": {
+	"double_damage_to": [{"name": "grass"}, {"name": "fighting"}, {"name": "bug"}],
+	"half_damage_to": [{"name": "electric"}, {"name": "rock"}, {"name": "steel"}],
+	"no_damage_to": [],
+	"double_damage_from": [{"name": "electric"}, {"name": "ice"}, {"name": "rock"}],
+	"half_damage_from": [{"name": "grass"}, {"name": "fighting"}, {"name": "bug"}],
+	"no_damage_from": [{"name": "ground"}]
+}}`
+
+func TestDualTypeDefense(t *testing.T) {
+	c := New()
+	c.Add(decode(t, water))
+	c.Add(decode(t, flying))
+	if !c.Has("water") || !c.Has("flying") || c.Has("grass") {
+		t.Errorf("unexpected loaded types")
+	}
+
+	// gyarados
+	actual := c.Defense([]string{"water", "flying"})
+	expected := []Matchup{
+		{Type: "electric", Multiplier: 4},
+		{Type: "rock", Multiplier: 2},
+		{Type: "bug", Multiplier: 0.5},
+		{Type: "fighting", Multiplier: 0.5},
+		{Type: "fire", Multiplier: 0.5},
+		{Type: "steel", Multiplier: 0.5},
+		{Type: "water", Multiplier: 0.5},
+		{Type: "ground", Multiplier: 0},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v, but got %v.", expected, actual)
+	}
+}
+
+func TestOffense(t *testing.T) {
+	c := New()
+	c.Add(decode(t, water))
+	c.Add(decode(t, flying))
+	actual := c.Offense([]string{"water", "flying"}, []string{"ground", "rock"})
+	expected := []Matchup{{Type: "water", Multiplier: 4}, {Type: "flying", Multiplier: 0.5}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v, but got %v.", expected, actual)
+	}
+	if m := c.Effectiveness("", []string{"water"}); m != 1 {
+		t.Errorf("expected typeless moves to be neutral, got %v", m)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/lulock/pokedex/internal/bag"
+)
+
+type bagItemDoc struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type bagDoc struct {
+	Money int          `json:"money"`
+	Items []bagItemDoc `json:"items"`
+}
+
+type shopItemDoc struct {
+	Name string `json:"name"`
+	Cost int    `json:"cost"`
+}
+
+type shopDoc struct {
+	Money int           `json:"money"`
+	Items []shopItemDoc `json:"items"`
+}
+
+type buyDoc struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+	Spent    int    `json:"spent"`
+	Money    int    `json:"money"`
+}
+
+// lists what's in the bag and how much money is left
+func commandBag(conf *config, args ...string) error {
+	doc := bagDoc{Money: conf.Bag.Money, Items: []bagItemDoc{}}
+	for _, name := range conf.Bag.Names() {
+		doc.Items = append(doc.Items, bagItemDoc{Name: name, Count: conf.Bag.Count(name)})
+	}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("Money: ₽%v", doc.Money))
+		if len(doc.Items) == 0 {
+			fmt.Fprintln(conf.Out, "Your bag is empty.")
+			return
+		}
+		fmt.Fprintln(conf.Out, "Your bag:")
+		for _, item := range doc.Items {
+			fmt.Fprintln(conf.Out, fmt.Sprintf(" . %v x%v", item.Name, item.Count))
+		}
+	})
+}
+
+// lists what the shop sells, prices come from the item endpoint. items the
+// API gives no price for (like the master ball) aren't for sale.
+func commandShop(conf *config, args ...string) error {
+	doc := shopDoc{Money: conf.Bag.Money, Items: []shopItemDoc{}}
+	for _, name := range bag.Known() {
+		item, err := conf.Client.GetItem(name)
+		if err != nil {
+			return err
+		}
+		if item.Cost <= 0 {
+			continue
+		}
+		doc.Items = append(doc.Items, shopItemDoc{Name: name, Cost: item.Cost})
+	}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, "Welcome to the Poke Mart! 🛒")
+		for _, item := range doc.Items {
+			fmt.Fprintln(conf.Out, fmt.Sprintf(" . %v: ₽%v", item.Name, item.Cost))
+		}
+		fmt.Fprintln(conf.Out, fmt.Sprintf("You have ₽%v", doc.Money))
+	})
+}
+
+// buys one or more of an item from the shop
+func commandBuy(conf *config, args ...string) error {
+	name := args[0]
+	quantity := 1
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("quantity must be a positive number, got %q", args[1])
+		}
+		quantity = n
+	}
+	if _, err := bag.Lookup(name); err != nil {
+		return err
+	}
+	item, err := conf.Client.GetItem(name)
+	if err != nil {
+		return err
+	}
+	if item.Cost <= 0 {
+		return fmt.Errorf("%v is not for sale", name)
+	}
+	if err := conf.Bag.Buy(name, quantity, item.Cost); err != nil {
+		return err
+	}
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your bag: %w", err)
+	}
+
+	doc := buyDoc{Item: name, Quantity: quantity, Spent: quantity * item.Cost, Money: conf.Bag.Money}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("Bought %v %v for ₽%v. You have ₽%v left.", doc.Quantity, doc.Item, doc.Spent, doc.Money))
+	})
+}
+
+// items the shop could sell, for completion
+func completeItems(conf *config) []string {
+	return bag.Known()
+}
+
+// balls in the bag, for completing --ball
+func completeBalls(conf *config) []string {
+	balls := []string{}
+	for _, name := range conf.Bag.Names() {
+		if item, err := bag.Lookup(name); err == nil && item.Kind == bag.Ball {
+			balls = append(balls, name)
+		}
+	}
+	return balls
+}
+
+// potions in the bag, for completing use
+func completePotions(conf *config) []string {
+	potions := []string{}
+	for _, name := range conf.Bag.Names() {
+		if item, err := bag.Lookup(name); err == nil && item.Kind == bag.Potion {
+			potions = append(potions, name)
+		}
+	}
+	return potions
+}
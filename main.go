@@ -1,14 +1,27 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"strings"
-	"bufio"
-	"os"
+	"flag"
 	"net/http"
-	"encoding/json"
-	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"github.com/lulock/pokedex/internal/bag"
+	"github.com/lulock/pokedex/internal/capture"
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/growth"
+	"github.com/lulock/pokedex/internal/learnset"
+	"github.com/lulock/pokedex/internal/encounter"
+	"github.com/lulock/pokedex/internal/fixture"
+	"github.com/lulock/pokedex/internal/lineedit"
+	"github.com/lulock/pokedex/internal/pokeapi"
 	"github.com/lulock/pokedex/internal/pokecache"
+	"github.com/lulock/pokedex/internal/save"
+	"github.com/lulock/pokedex/internal/typechart"
 	"time"
 	"math/rand"
 )
@@ -18,358 +31,50 @@ import (
 type cliCommand struct {
 	name string
 	description string
+	usage string // synopsis of the positional args, e.g. "<area>"
+	minArgs int
+	maxArgs int // -1 means any number of args
+	flags map[string]bool // --flags the command accepts, true when the flag takes a value
+	keepCase []int // positional args passed on as typed instead of lowercased, like file paths
+	complete func(*config) []string // candidates for tab completing the args
+	completeFlags map[string]func(*config) []string // candidates for the values of --flags
 	callback func(*config, ...string) error
 }
 
 type config struct {
 	Next string
 	Previous string
-	Cache *pokecache.Cache
-	Pokedex map[string]pokemon
+	Client *pokeapi.Client
+	Pokedex map[string]pokeapi.Pokemon
+	SavePath string // where the pokedex is persisted, empty means don't persist
+	SaveBase string // the default trainer's save, other trainers' saves live next to it
+	Profile string // the trainer playing
+	Out io.Writer // where commands print to
+	Rand *rand.Rand // source of randomness for catching, seeded in tests
+	SeenAreas map[string]bool // location areas listed by map, for completion
+	Output string // outputText or outputJSON
+	Bag bag.Bag // items and money
+	Location string // the area last explored, where wild pokemon come from
+	Wild *encounter.Wild // the wild pokemon in front of the trainer, if any
+	Owned collection.Collection // every pokemon the trainer has, Pokedex only holds species data
+	VersionGroup string // the games whose learnsets pokemon learn moves from
+	Battle *battleState // the battle going on, if any
+	Types *typechart.Chart // type matchups fetched so far, loaded lazily by loadTypes
+	Stats save.Stats // running totals for the trainer playing
 }
 
-type LocationAreas struct {
-	Count    int    `json:"count"`
-	Next     string `json:"next"`
-	Previous string `json:"previous"`
-	Results  []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"results"`
-}
-
-type pokemonInArea struct {
-	EncounterMethodRates []struct {
-		EncounterMethod struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"encounter_method"`
-		VersionDetails []struct {
-			Rate    int `json:"rate"`
-			Version struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
-		} `json:"version_details"`
-	} `json:"encounter_method_rates"`
-	GameIndex int `json:"game_index"`
-	ID        int `json:"id"`
-	Location  struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"location"`
-	Name  string `json:"name"`
-	Names []struct {
-		Language struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
-		Name string `json:"name"`
-	} `json:"names"`
-	PokemonEncounters []struct {
-		Pokemon struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"pokemon"`
-		VersionDetails []struct {
-			EncounterDetails []struct {
-				Chance          int   `json:"chance"`
-				ConditionValues []any `json:"condition_values"`
-				MaxLevel        int   `json:"max_level"`
-				Method          struct {
-					Name string `json:"name"`
-					URL  string `json:"url"`
-				} `json:"method"`
-				MinLevel int `json:"min_level"`
-			} `json:"encounter_details"`
-			MaxChance int `json:"max_chance"`
-			Version   struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
-		} `json:"version_details"`
-	} `json:"pokemon_encounters"`
-}
-type pokemon struct {
-	Abilities []struct {
-		Ability struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"ability"`
-		IsHidden bool `json:"is_hidden"`
-		Slot     int  `json:"slot"`
-	} `json:"abilities"`
-	BaseExperience int `json:"base_experience"`
-	Cries          struct {
-		Latest string `json:"latest"`
-		Legacy string `json:"legacy"`
-	} `json:"cries"`
-	Forms []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"forms"`
-	GameIndices []struct {
-		GameIndex int `json:"game_index"`
-		Version   struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"version"`
-	} `json:"game_indices"`
-	Height    int `json:"height"`
-	HeldItems []struct {
-		Item struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"item"`
-		VersionDetails []struct {
-			Rarity  int `json:"rarity"`
-			Version struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
-		} `json:"version_details"`
-	} `json:"held_items"`
-	ID                     int    `json:"id"`
-	IsDefault              bool   `json:"is_default"`
-	LocationAreaEncounters string `json:"location_area_encounters"`
-	Moves                  []struct {
-		Move struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"move"`
-		VersionGroupDetails []struct {
-			LevelLearnedAt  int `json:"level_learned_at"`
-			MoveLearnMethod struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"move_learn_method"`
-			Order        any `json:"order"`
-			VersionGroup struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version_group"`
-		} `json:"version_group_details"`
-	} `json:"moves"`
-	Name          string `json:"name"`
-	Order         int    `json:"order"`
-	PastAbilities []struct {
-		Abilities []struct {
-			Ability  any  `json:"ability"`
-			IsHidden bool `json:"is_hidden"`
-			Slot     int  `json:"slot"`
-		} `json:"abilities"`
-		Generation struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"generation"`
-	} `json:"past_abilities"`
-	PastTypes []any `json:"past_types"`
-	Species   struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"species"`
-	Sprites struct {
-		BackDefault      string `json:"back_default"`
-		BackFemale       string `json:"back_female"`
-		BackShiny        string `json:"back_shiny"`
-		BackShinyFemale  string `json:"back_shiny_female"`
-		FrontDefault     string `json:"front_default"`
-		FrontFemale      string `json:"front_female"`
-		FrontShiny       string `json:"front_shiny"`
-		FrontShinyFemale string `json:"front_shiny_female"`
-		Other            struct {
-			DreamWorld struct {
-				FrontDefault string `json:"front_default"`
-				FrontFemale  any    `json:"front_female"`
-			} `json:"dream_world"`
-			Home struct {
-				FrontDefault     string `json:"front_default"`
-				FrontFemale      string `json:"front_female"`
-				FrontShiny       string `json:"front_shiny"`
-				FrontShinyFemale string `json:"front_shiny_female"`
-			} `json:"home"`
-			OfficialArtwork struct {
-				FrontDefault string `json:"front_default"`
-				FrontShiny   string `json:"front_shiny"`
-			} `json:"official-artwork"`
-			Showdown struct {
-				BackDefault      string `json:"back_default"`
-				BackFemale       string `json:"back_female"`
-				BackShiny        string `json:"back_shiny"`
-				BackShinyFemale  any    `json:"back_shiny_female"`
-				FrontDefault     string `json:"front_default"`
-				FrontFemale      string `json:"front_female"`
-				FrontShiny       string `json:"front_shiny"`
-				FrontShinyFemale string `json:"front_shiny_female"`
-			} `json:"showdown"`
-		} `json:"other"`
-		Versions struct {
-			GenerationI struct {
-				RedBlue struct {
-					BackDefault      string `json:"back_default"`
-					BackGray         string `json:"back_gray"`
-					BackTransparent  string `json:"back_transparent"`
-					FrontDefault     string `json:"front_default"`
-					FrontGray        string `json:"front_gray"`
-					FrontTransparent string `json:"front_transparent"`
-				} `json:"red-blue"`
-				Yellow struct {
-					BackDefault      string `json:"back_default"`
-					BackGray         string `json:"back_gray"`
-					BackTransparent  string `json:"back_transparent"`
-					FrontDefault     string `json:"front_default"`
-					FrontGray        string `json:"front_gray"`
-					FrontTransparent string `json:"front_transparent"`
-				} `json:"yellow"`
-			} `json:"generation-i"`
-			GenerationIi struct {
-				Crystal struct {
-					BackDefault           string `json:"back_default"`
-					BackShiny             string `json:"back_shiny"`
-					BackShinyTransparent  string `json:"back_shiny_transparent"`
-					BackTransparent       string `json:"back_transparent"`
-					FrontDefault          string `json:"front_default"`
-					FrontShiny            string `json:"front_shiny"`
-					FrontShinyTransparent string `json:"front_shiny_transparent"`
-					FrontTransparent      string `json:"front_transparent"`
-				} `json:"crystal"`
-				Gold struct {
-					BackDefault      string `json:"back_default"`
-					BackShiny        string `json:"back_shiny"`
-					FrontDefault     string `json:"front_default"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontTransparent string `json:"front_transparent"`
-				} `json:"gold"`
-				Silver struct {
-					BackDefault      string `json:"back_default"`
-					BackShiny        string `json:"back_shiny"`
-					FrontDefault     string `json:"front_default"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontTransparent string `json:"front_transparent"`
-				} `json:"silver"`
-			} `json:"generation-ii"`
-			GenerationIii struct {
-				Emerald struct {
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
-				} `json:"emerald"`
-				FireredLeafgreen struct {
-					BackDefault  string `json:"back_default"`
-					BackShiny    string `json:"back_shiny"`
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
-				} `json:"firered-leafgreen"`
-				RubySapphire struct {
-					BackDefault  string `json:"back_default"`
-					BackShiny    string `json:"back_shiny"`
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
-				} `json:"ruby-sapphire"`
-			} `json:"generation-iii"`
-			GenerationIv struct {
-				DiamondPearl struct {
-					BackDefault      string `json:"back_default"`
-					BackFemale       string `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  string `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      string `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale string `json:"front_shiny_female"`
-				} `json:"diamond-pearl"`
-				HeartgoldSoulsilver struct {
-					BackDefault      string `json:"back_default"`
-					BackFemale       string `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  string `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      string `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale string `json:"front_shiny_female"`
-				} `json:"heartgold-soulsilver"`
-				Platinum struct {
-					BackDefault      string `json:"back_default"`
-					BackFemale       string `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  string `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      string `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale string `json:"front_shiny_female"`
-				} `json:"platinum"`
-			} `json:"generation-iv"`
-			GenerationV struct {
-				BlackWhite struct {
-					Animated struct {
-						BackDefault      string `json:"back_default"`
-						BackFemale       string `json:"back_female"`
-						BackShiny        string `json:"back_shiny"`
-						BackShinyFemale  string `json:"back_shiny_female"`
-						FrontDefault     string `json:"front_default"`
-						FrontFemale      string `json:"front_female"`
-						FrontShiny       string `json:"front_shiny"`
-						FrontShinyFemale string `json:"front_shiny_female"`
-					} `json:"animated"`
-					BackDefault      string `json:"back_default"`
-					BackFemale       string `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  string `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      string `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale string `json:"front_shiny_female"`
-				} `json:"black-white"`
-			} `json:"generation-v"`
-			GenerationVi struct {
-				OmegarubyAlphasapphire struct {
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      string `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale string `json:"front_shiny_female"`
-				} `json:"omegaruby-alphasapphire"`
-				XY struct {
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      string `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale string `json:"front_shiny_female"`
-				} `json:"x-y"`
-			} `json:"generation-vi"`
-			GenerationVii struct {
-				Icons struct {
-					FrontDefault string `json:"front_default"`
-					FrontFemale  any    `json:"front_female"`
-				} `json:"icons"`
-				UltraSunUltraMoon struct {
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      string `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale string `json:"front_shiny_female"`
-				} `json:"ultra-sun-ultra-moon"`
-			} `json:"generation-vii"`
-			GenerationViii struct {
-				Icons struct {
-					FrontDefault string `json:"front_default"`
-					FrontFemale  string `json:"front_female"`
-				} `json:"icons"`
-			} `json:"generation-viii"`
-		} `json:"versions"`
-	} `json:"sprites"`
-	Stats []struct {
-		BaseStat int `json:"base_stat"`
-		Effort   int `json:"effort"`
-		Stat     struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"stat"`
-	} `json:"stats"`
-	Types []struct {
-		Slot int `json:"slot"`
-		Type struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"type"`
-	} `json:"types"`
-	Weight int `json:"weight"`
+// writes the pokedex to the save file so it survives the session
+func (conf *config) save() error {
+	if conf.SavePath == "" {
+		return nil
+	}
+	f := save.New()
+	f.Pokedex = conf.Pokedex
+	f.Bag = conf.Bag
+	f.Location = conf.Location
+	f.Owned = conf.Owned
+	f.Stats = conf.Stats
+	return save.Write(conf.SavePath, f)
 }
 
 // cleans input by removing whitespace and returning slice of all words in lowercase
@@ -382,228 +87,367 @@ func cleanInput(text string) []string {
 
 // exits the programme
 func commandExit(conf *config, args ...string) error {
-	fmt.Println("Closing the Pokedex... Goodbye!")
-	os.Exit(0)
-	return nil
+	msg := "Closing the Pokedex... Goodbye!"
+	conf.emit(messageDoc{Message: msg}, func() {
+		fmt.Fprintln(conf.Out, msg)
+	})
+	return errExit
 }
 
 // displays the names of 20 location areas in the Pokemon world
 func commandMap(conf *config, args ...string) error {
-	// first check cache?
-	data, exists := conf.Cache.Get(conf.Next)
-	locAreas := LocationAreas{}
-	// if it does not exist,get
-	if !exists {
-		resp, err := http.Get(conf.Next)
-		if err != nil {
-			fmt.Println(fmt.Sprintf("Could not get locations: %v", err))
-			return err
-		}
-		data, err = io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-		if err != nil {
-			fmt.Println(err)
-			return err
-		}
-		// now add to cache
-		conf.Cache.Add(conf.Next, data)
-
-	} else {
-		fmt.Println("using cache to go forwards!")
-	}
-
-	err := json.Unmarshal(data, &locAreas)
-	if err != nil {
-		fmt.Println(err)
-		return err
-	}
-	
-	for _, loc := range locAreas.Results {
-		fmt.Println(loc.Name)
+	if conf.Next == "" {
+		return conf.emit(locationPageDoc{Locations: []namedResource{}, Previous: conf.Previous}, func() {
+			fmt.Fprintln(conf.Out, "you're on the last page")
+		})
 	}
-	conf.Next = locAreas.Next
-	conf.Previous = locAreas.Previous
-	return nil
+	return showLocationPage(conf, conf.Next)
 }
 
 // displays the names of 20 previous locations 
 func commandMapb(conf *config, args ...string) error {
-	// first check cache?
-	data, exists := conf.Cache.Get(conf.Previous)
-	// if it does not exist,get
-	if !exists {
-		resp, err := http.Get(conf.Previous)
-		if err != nil {
-			fmt.Println(fmt.Sprintf("Could not get locations: %v", err))
-			return err
-		}
-		data, err = io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-		if err != nil {
-			fmt.Println(err)
-			return err
-		}
-		// now add to cache
-		conf.Cache.Add(conf.Previous, data)
-	} else {
-		fmt.Println("using cache 2 go back")
+	if conf.Previous == "" {
+		return conf.emit(locationPageDoc{Locations: []namedResource{}, Next: conf.Next}, func() {
+			fmt.Fprintln(conf.Out, "you're on the first page")
+		})
 	}
+	return showLocationPage(conf, conf.Previous)
+}
 
-
-	locAreas := LocationAreas{}
-
-	err := json.Unmarshal(data, &locAreas)
-	
+// shows the page of locations at pageURL and moves the paging cursors there
+func showLocationPage(conf *config, pageURL string) error {
+	locAreas, err := conf.Client.ListLocationAreas(pageURL)
 	if err != nil {
-		fmt.Println(err)
-		return err
+		return fmt.Errorf("Could not get locations: %w", err)
+	}
+
+	doc := locationPageDoc{
+		Locations: []namedResource{},
+		Next: locAreas.Next,
+		Previous: locAreas.Previous,
 	}
-	
 	for _, loc := range locAreas.Results {
-		fmt.Println(loc.Name)
+		doc.Locations = append(doc.Locations, namedResource{Name: loc.Name, URL: loc.URL})
+		conf.SeenAreas[loc.Name] = true
 	}
 	conf.Next = locAreas.Next
 	conf.Previous = locAreas.Previous
-	return nil
+	return conf.emit(doc, func() {
+		for _, loc := range locAreas.Results {
+			fmt.Fprintln(conf.Out, loc.Name)
+		}
+	})
 }
 
 // explore command takes the name of a location area and lists 
 // all the Pokemon located there.
 func commandExplore(conf *config, args ...string) error {
-	fmt.Println(fmt.Sprintf("Looking around %v for pokemon 🧐", args[0]))
-	url := fmt.Sprintf("https://pokeapi.co/api/v2/location-area/%v", args[0])
-	// first check cache?
-	data, exists := conf.Cache.Get(url)
-	// if it does not exist,get
-	if !exists {
-		resp, err := http.Get(url)
-		if err != nil {
-			return err
-		}
-		data, err = io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-		if err != nil {
-			fmt.Println(err)
-			return err
-		}
-		// now add to cache
-		conf.Cache.Add(url, data)
-	} else {
-		fmt.Println("using cache 2 list pokemon")
+	if conf.Battle != nil {
+		return errInBattle
 	}
-
-	pokemon := pokemonInArea{}
-	err := json.Unmarshal(data, &pokemon)
+	if conf.Output == outputText {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("Looking around %v for pokemon 🧐", args[0]))
+	}
+	area, err := conf.Client.GetLocationArea(args[0])
 	if err != nil {
 		return err
 	}
-	fmt.Println("Found these fellas:")
-	for _, poke := range pokemon.PokemonEncounters {
-		fmt.Println(fmt.Sprintf(". %v", poke.Pokemon.Name))
+	// exploring an area takes the trainer there
+	conf.Location = area.Name
+	conf.Wild = nil
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your location: %w", err)
 	}
-	return nil
+	return conf.emit(newExploreDoc(area), func() {
+		fmt.Fprintln(conf.Out, "Found these fellas:")
+		for _, poke := range area.PokemonEncounters {
+			fmt.Fprintln(conf.Out, fmt.Sprintf(". %v", poke.Pokemon.Name))
+		}
+	})
 }
 
-// catch command takes the name of a pokemon and tries to catch them
+// catch command takes the name of a pokemon living in the current area, or
+// the wild pokemon that just appeared, and tries to catch them.
+// it throws a poke ball unless --ball picks another one from the bag.
 func commandCatch(conf *config, args ...string) error {
-	pokename := args[0]
-	fmt.Println(fmt.Sprintf("Throwing a Pokeball at %v...", pokename))
-	url := fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/%v", pokename)
-	// first check cache?
-	data, exists := conf.Cache.Get(url)
-	// if it does not exist,get
-	if !exists {
-		resp, err := http.Get(url)
-		if err != nil {
-			return err
+	positional, flags := splitFlags(args)
+	pokename := ""
+	if len(positional) > 0 {
+		pokename = positional[0]
+	}
+	ballName := bag.DefaultBall
+	if flags["ball"] != "" {
+		ballName = flags["ball"]
+	}
+	ball, err := bag.Lookup(ballName)
+	if err != nil {
+		return err
+	}
+	if ball.Kind != bag.Ball {
+		return fmt.Errorf("%v is not a ball", ballName)
+	}
+	if conf.Bag.Count(ballName) == 0 {
+		return fmt.Errorf("you're out of %v, buy some more at the shop", ballName)
+	}
+
+	state := conf.Battle
+	if state != nil {
+		if state.trainer != "" {
+			return errors.New("you can't catch another trainer's pokemon!")
 		}
-		data, err = io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-		if err != nil {
-			fmt.Println(err)
-			return err
+		if pokename != "" && pokename != state.foe.Pokemon {
+			return fmt.Errorf("you're battling %v, catch it or run", state.foe.Pokemon)
 		}
-		// now add to cache
-		conf.Cache.Add(url, data)
-	} else {
-		fmt.Println("using cache 2 try catchin pokemon again")
 	}
-
-	pokemon := pokemon{}
-	err := json.Unmarshal(data, &pokemon)
+	wild, err := wildTarget(conf, pokename)
+	if err != nil {
+		return err
+	}
+	pokemon, err := conf.Client.GetPokemon(wild.Pokemon)
+	if err != nil {
+		return err
+	}
+	species, err := conf.Client.GetPokemonSpecies(pokemon.Species.Name)
 	if err != nil {
 		return err
 	}
-	randomInt := rand.Intn(100)
-	chance := (340 - pokemon.BaseExperience)/340
-	if chance < 30 {
-		chance = 30
+	if conf.Output == outputText {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("Throwing a %v at %v (Lv. %v)...", ballName, wild.Pokemon, wild.Level))
+	}
+
+	// the wild pokemon has no status for now, it's at full health unless
+	// it's been worn down in a battle
+	attempt := capture.Attempt{
+		CaptureRate: species.CaptureRate,
+		MaxHP: baseStat(pokemon, "hp"),
+		Ball: ball.BallBonus,
+		Status: capture.None,
+	}
+	if state != nil {
+		foe := state.battle.Sides[1]
+		attempt.MaxHP = foe.Stats.HP
+		attempt.CurrentHP = foe.HP
 	}
-	if chance > 90 {
-		chance = 90
+	shakes, isCaught := capture.Throw(attempt, conf.Rand)
+	conf.Bag.Take(ballName)
+
+	reward := 0
+	owned := collection.Owned{}
+	box := 0
+	var levelUp *levelUpDoc
+	var expErr error
+	var fight *battleDoc
+	// an escaped pokemon sticks around for another throw
+	conf.Wild = &wild
+	if !isCaught {
+		conf.Stats.Escaped++
 	}
-	
-	isCaught := randomInt < chance
-	
 	if isCaught {
-		fmt.Println(fmt.Sprintf("%v was caught!", pokemon.Name))
+		conf.Wild = nil
+		conf.Battle = nil
 		conf.Pokedex[pokemon.Name] = pokemon
-	} else {	
-		fmt.Println(fmt.Sprintf("%v escaped!", pokemon.Name))
+		// the lead earns the experience, not the pokemon that's joining it
+		leadID := 0
+		if lead := leadPokemon(conf); lead != nil {
+			leadID = lead.ID
+		}
+		owned = collection.Owned{
+			Species: pokemon.Name,
+			PokemonID: pokemon.ID,
+			Level: wild.Level,
+			CaughtAt: time.Now(),
+			CaughtIn: conf.Location,
+			Shiny: conf.Rand.Intn(collection.ShinyOdds) == 0,
+		}
+		if err := startingProgress(conf, &owned, species.GrowthRate.Name); err != nil {
+			return err
+		}
+		owned, box = conf.Owned.Add(owned)
+		// stronger pokemon are worth more
+		reward = pokemon.BaseExperience
+		conf.Bag.Money += reward
+		conf.Stats.Caught++
+		conf.Stats.MoneyEarned += reward
+
+		// catching is worth as much experience as winning a battle. the catch
+		// is already made, so a failure here is only reported afterwards.
+		if lead, err := conf.Owned.Get(leadID); err == nil {
+			doc, err := gainExperience(conf, lead, growth.Yield(pokemon.BaseExperience, wild.Level))
+			if err != nil {
+				expErr = fmt.Errorf("%v couldn't gain experience: %w", lead.Name(), err)
+			} else {
+				levelUp = &doc
+			}
+		}
+	} else if state != nil {
+		// throwing a ball uses up the turn, the foe gets to attack
+		doc := newBattleDoc(state)
+		doc.Events = newBattleEventDocs(state.battle.Act(1, foeMove(state)))
+		if err := settleBattle(conf, &doc); err != nil {
+			return err
+		}
+		fight = &doc
+	}
+	// the ball is gone either way, so save after every throw
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your pokedex: %w", err)
 	}
 
-	return nil
+	doc := catchDoc{
+		Pokemon: pokemon.Name,
+		Level: wild.Level,
+		Caught: isCaught,
+		Shakes: shakes,
+		Chance: capture.Chance(attempt),
+		Ball: ballName,
+		Reward: reward,
+		ID: owned.ID,
+		Shiny: owned.Shiny,
+		Box: box,
+		LevelUp: levelUp,
+		Battle: fight,
+	}
+	err = conf.emit(doc, func() {
+		if isCaught {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("%v was caught!", pokemon.Name))
+			if owned.Shiny {
+				fmt.Fprintln(conf.Out, "It's shiny! ✨")
+			}
+			fmt.Fprintln(conf.Out, fmt.Sprintf("It was added to your Pokedex as #%v.", owned.ID))
+			if box != 0 {
+				fmt.Fprintln(conf.Out, fmt.Sprintf("Your party is full, so it was sent to box %v.", box))
+			}
+			fmt.Fprintln(conf.Out, fmt.Sprintf("You earned ₽%v.", reward))
+			if levelUp != nil {
+				printLevelUp(conf, *levelUp)
+			}
+		} else {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("%v escaped!", pokemon.Name))
+			if fight != nil {
+				printBattle(conf, *fight)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return expErr
+}
+
+// returns the base value of the named stat, 0 when the pokemon doesn't have it
+func baseStat(pokemon pokeapi.Pokemon, name string) int {
+	for _, stat := range pokemon.Stats {
+		if stat.Stat.Name == name {
+			return stat.BaseStat
+		}
+	}
+	return 0
 }
 
+// inspect command shows one owned pokemon when given an id, or everything
+// known about a caught species when given a name
 func commandInspect(conf *config, args ...string) error {
+	if _, err := collection.ParseID(args[0]); err == nil {
+		return inspectOwned(conf, args[0])
+	}
 	pokename := args[0]
 	pokemon, ok := conf.Pokedex[pokename]
 	if !ok {
-		fmt.Println(fmt.Sprintf("you have not caught that pokemon"))
-	} else {
-		fmt.Println(fmt.Sprintf("Name: %v", pokemon.Name))
-		fmt.Println(fmt.Sprintf("Height: %v", pokemon.Height))
-		fmt.Println(fmt.Sprintf("Weight: %v", pokemon.Weight))
-		fmt.Println(fmt.Sprintf("Stats:"))
-		fmt.Println(fmt.Sprintf("  . hp: %v", pokemon.Stats[0].BaseStat))
-		fmt.Println(fmt.Sprintf("  . attack: %v", pokemon.Stats[1].BaseStat))
-		fmt.Println(fmt.Sprintf("  . defense: %v", pokemon.Stats[2].BaseStat))
-		fmt.Println(fmt.Sprintf("  . special-attack: %v", pokemon.Stats[3].BaseStat))
-		fmt.Println(fmt.Sprintf("  . special-defense: %v", pokemon.Stats[4].BaseStat))
-		fmt.Println(fmt.Sprintf("  . speed: %v", pokemon.Stats[5].BaseStat))
-		fmt.Println(fmt.Sprintf("Types:"))
-		for _, poketype := range pokemon.Types {
-			fmt.Println(fmt.Sprintf("  . %v", poketype.Type.Name))
-		}
+		return conf.emit(inspectDoc{Name: pokename}, func() {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("you have not caught that pokemon"))
+		})
 	}
-	
-	return nil
+
+	doc := newInspectDoc(pokemon)
+	doc.Owned = conf.Owned.OfSpecies(pokename)
+	return conf.emit(doc, func() {
+		printSpecies(conf, pokemon)
+		if len(doc.Owned) > 0 {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("Owned:"))
+			for _, owned := range doc.Owned {
+				fmt.Fprintln(conf.Out, fmt.Sprintf("  . %v", owned))
+			}
+		}
+	})
 }
 
-func commandPokedex(conf *config, args ...string) error {
-	if len(conf.Pokedex) == 0 {
-		fmt.Println("You haven't caught any Pokemon yet! Use the Catch command and try to catch 'em all.")
-	} else {
-		fmt.Println("Your Pokedex:")
-		for k, _ := range (conf.Pokedex) {
-			fmt.Println(fmt.Sprintf(" . %v", k))
+func inspectOwned(conf *config, ref string) error {
+	owned, err := conf.Owned.Lookup(ref)
+	if err != nil {
+		return err
+	}
+	pokemon, err := speciesData(conf, owned.Species)
+	if err != nil {
+		return err
+	}
+
+	box, slot, err := conf.Owned.Where(owned.ID)
+	if err != nil {
+		return err
+	}
+	doc := ownedInspectDoc{Owned: *owned, InParty: box == 0, Box: box, Pokemon: newInspectDoc(pokemon)}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("ID: #%v", owned.ID))
+		if owned.Nickname != "" {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("Nickname: %v", owned.Nickname))
+		}
+		fmt.Fprintln(conf.Out, fmt.Sprintf("Level: %v", owned.Level))
+		fmt.Fprintln(conf.Out, fmt.Sprintf("Experience: %v", owned.Experience))
+		if box == 0 {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("Party: slot %v", slot+1))
+		} else {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("Box: %v", box))
+		}
+		if len(owned.Moves) > 0 {
+			fmt.Fprintln(conf.Out, "Moves:")
+			for _, move := range owned.Moves {
+				fmt.Fprintln(conf.Out, fmt.Sprintf("  . %v", move))
+			}
 		}
+		if !owned.CaughtAt.IsZero() {
+			caught := owned.CaughtAt.Format("2006-01-02")
+			if owned.CaughtIn != "" {
+				caught += " in " + owned.CaughtIn
+			}
+			fmt.Fprintln(conf.Out, fmt.Sprintf("Caught: %v", caught))
+		}
+		if owned.Shiny {
+			fmt.Fprintln(conf.Out, "Shiny: yes ✨")
+		}
+		printSpecies(conf, pokemon)
+	})
+}
+
+// prints the species data every pokemon of that species shares
+func printSpecies(conf *config, pokemon pokeapi.Pokemon) {
+	fmt.Fprintln(conf.Out, fmt.Sprintf("Name: %v", pokemon.Name))
+	fmt.Fprintln(conf.Out, fmt.Sprintf("Height: %v", pokemon.Height))
+	fmt.Fprintln(conf.Out, fmt.Sprintf("Weight: %v", pokemon.Weight))
+	fmt.Fprintln(conf.Out, fmt.Sprintf("Stats:"))
+	fmt.Fprintln(conf.Out, fmt.Sprintf("  . hp: %v", pokemon.Stats[0].BaseStat))
+	fmt.Fprintln(conf.Out, fmt.Sprintf("  . attack: %v", pokemon.Stats[1].BaseStat))
+	fmt.Fprintln(conf.Out, fmt.Sprintf("  . defense: %v", pokemon.Stats[2].BaseStat))
+	fmt.Fprintln(conf.Out, fmt.Sprintf("  . special-attack: %v", pokemon.Stats[3].BaseStat))
+	fmt.Fprintln(conf.Out, fmt.Sprintf("  . special-defense: %v", pokemon.Stats[4].BaseStat))
+	fmt.Fprintln(conf.Out, fmt.Sprintf("  . speed: %v", pokemon.Stats[5].BaseStat))
+	fmt.Fprintln(conf.Out, fmt.Sprintf("Types:"))
+	for _, poketype := range pokemon.Types {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("  . %v", poketype.Type.Name))
 	}
-	return nil
 }
 
-func main() {
-	scanner := bufio.NewScanner(os.Stdin) // wait for user input using bufio.NewScanner which blocks the code and waits for input, once the user types something and presses enter, the code continues and the input is available in the returned bufio.Scanner
-	// make a cache
-	// const duration := 5 * time.Millisecond
-	// cache := NewCache(duration)
-	// map the supported commands:
-	conf := config{
-		Next: "https://pokeapi.co/api/v2/location-area/",
-		Cache: pokecache.NewCache(5 * time.Second),
-		Pokedex: make(map[string]pokemon),
+// reports whether stdin is a pipe or a file rather than a terminal. the
+// line editor can't tell on platforms without raw mode, so ask the os.
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
 	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
 
+// returns the registry of every command the pokedex understands
+func getCommands() map[string]cliCommand {
 	validCommands := map[string]cliCommand{
 		"exit": {
 			name: "exit",
@@ -622,63 +466,367 @@ func main() {
 		},
 		"explore" : {
 			name: "explore",
+			usage: "<area>",
+			minArgs: 1,
+			maxArgs: 1,
 			description: "Displays a list of all Pokemon located in the area passed as input",
+			complete: completeAreas,
 			callback: commandExplore,
 		},
 		"catch" : {
 			name: "catch",
-			description: "Tries to catch a Pokemon",
+			usage: "[pokemon]",
+			minArgs: 0,
+			maxArgs: 1,
+			flags: map[string]bool{"ball": true},
+			completeFlags: map[string]func(*config) []string{"ball": completeBalls},
+			description: "Tries to catch the wild Pokemon in front of you, or one living in the current area",
+			complete: completeWildPokemon,
 			callback: commandCatch,
 		},
+		"encounter" : {
+			name: "encounter",
+			description: "Looks for a wild Pokemon in the area you're exploring",
+			callback: commandEncounter,
+		},
 		"inspect" : {
 			name: "inspect",
-			description: "Inspects Pokemon",
+			usage: "<pokemon|id>",
+			minArgs: 1,
+			maxArgs: 1,
+			description: "Inspects a caught Pokemon species, or one of your Pokemon by id",
+			complete: func(conf *config) []string {
+				return append(completeCaughtPokemon(conf), conf.Owned.IDs()...)
+			},
 			callback: commandInspect,
 		},
+		"nickname" : {
+			name: "nickname",
+			usage: "<id> [name]",
+			minArgs: 1,
+			maxArgs: 2,
+			keepCase: []int{1},
+			description: "Gives one of your Pokemon a nickname, leave the name out to clear it",
+			complete: completeOwned,
+			callback: commandNickname,
+		},
+		"release" : {
+			name: "release",
+			usage: "<id>",
+			minArgs: 1,
+			maxArgs: 1,
+			description: "Releases one of your Pokemon back into the wild",
+			complete: completeOwned,
+			callback: commandRelease,
+		},
+		"export" : {
+			name: "export",
+			usage: "<id> <file>",
+			minArgs: 2,
+			maxArgs: 2,
+			keepCase: []int{1},
+			description: "Sends one of your Pokemon away into a file another trainer can import",
+			complete: completeOwned,
+			callback: commandExport,
+		},
+		"import" : {
+			name: "import",
+			usage: "<file>",
+			minArgs: 1,
+			maxArgs: 1,
+			keepCase: []int{0},
+			description: "Takes in a Pokemon exported by another trainer",
+			callback: commandImport,
+		},
+		"party" : {
+			name: "party",
+			description: "Lists the Pokemon in your party, the lead first",
+			callback: commandParty,
+		},
+		"box" : {
+			name: "box",
+			usage: "<n>",
+			minArgs: 1,
+			maxArgs: 1,
+			description: "Lists the Pokemon stored in a PC box",
+			callback: commandBox,
+		},
+		"deposit" : {
+			name: "deposit",
+			usage: "<id> [box]",
+			minArgs: 1,
+			maxArgs: 2,
+			description: "Moves a Pokemon from your party to a PC box",
+			complete: completeOwned,
+			callback: commandDeposit,
+		},
+		"withdraw" : {
+			name: "withdraw",
+			usage: "<id>",
+			minArgs: 1,
+			maxArgs: 1,
+			description: "Moves a Pokemon from its PC box to your party",
+			complete: completeOwned,
+			callback: commandWithdraw,
+		},
+		"swap" : {
+			name: "swap",
+			usage: "<id> <id>",
+			minArgs: 2,
+			maxArgs: 2,
+			description: "Swaps the places of two Pokemon, swap with your lead to pick a new one",
+			complete: completeOwned,
+			callback: commandSwap,
+		},
 		"pokedex" : {
 			name: "pokedex",
-			description: "Lists all caught Pokemon",
+			usage: "[search]",
+			minArgs: 0,
+			maxArgs: 1,
+			flags: pokedexFlags(),
+			completeFlags: map[string]func(*config) []string{"sort": completePokedexSorts},
+			description: "Lists your Pokemon, e.g. pokedex --type fire --min-speed 100 --sort attack, or pokedex pika",
 			callback: commandPokedex,
 		},
-
-
-
+		"battle" : {
+			name: "battle",
+			flags: map[string]bool{"trainer": false},
+			description: "Battles the wild Pokemon in front of you with your lead Pokemon, or a trainer with --trainer",
+			callback: commandBattle,
+		},
+		"fight" : {
+			name: "fight",
+			usage: "[move]",
+			minArgs: 0,
+			maxArgs: 1,
+			description: "Uses a move in the current battle, the strongest one when no move is given",
+			complete: completeBattleMoves,
+			callback: commandFight,
+		},
+		"use" : {
+			name: "use",
+			usage: "<potion>",
+			minArgs: 1,
+			maxArgs: 1,
+			description: "Uses a potion on your Pokemon in the current battle",
+			complete: completePotions,
+			callback: commandUse,
+		},
+		"run" : {
+			name: "run",
+			description: "Runs away from a wild Pokemon battle",
+			callback: commandRun,
+		},
+		"types" : {
+			name: "types",
+			usage: "<pokemon>",
+			minArgs: 1,
+			maxArgs: 1,
+			description: "Shows the weaknesses, resistances and immunities of a Pokemon's types",
+			complete: completeCaughtPokemon,
+			callback: commandTypes,
+		},
+		"matchup" : {
+			name: "matchup",
+			usage: "<pokemon> <pokemon>",
+			minArgs: 2,
+			maxArgs: 2,
+			description: "Shows how two Pokemon's types fare against each other",
+			complete: completeCaughtPokemon,
+			callback: commandMatchup,
+		},
+		"evolution" : {
+			name: "evolution",
+			usage: "<pokemon>",
+			minArgs: 1,
+			maxArgs: 1,
+			description: "Shows how a Pokemon evolves, marking the stages you've caught",
+			complete: completeCaughtPokemon,
+			callback: commandEvolution,
+		},
+		"bag" : {
+			name: "bag",
+			description: "Lists the items in your bag and your money",
+			callback: commandBag,
+		},
+		"shop" : {
+			name: "shop",
+			description: "Lists the items for sale at the Poke Mart",
+			callback: commandShop,
+		},
+		"buy" : {
+			name: "buy",
+			description: "Buys items from the Poke Mart",
+			usage: "<item> [quantity]",
+			minArgs: 1,
+			maxArgs: 2,
+			complete: completeItems,
+			callback: commandBuy,
+		},
+		"profile" : {
+			name: "profile",
+			usage: "<list|create|switch|delete> [trainer]",
+			minArgs: 1,
+			maxArgs: 2,
+			description: "Lists the trainers sharing this Pokedex, or creates, switches to or deletes one",
+			complete: completeProfiles,
+			callback: commandProfile,
+		},
+		"set" : {
+			name: "set",
+			description: "Changes a setting for this session, e.g. set output json or set version-group red-blue",
+			usage: "<setting> <value>",
+			minArgs: 2,
+			maxArgs: 2,
+			complete: func(conf *config) []string {
+				return []string{"output", outputText, outputJSON, "version-group"}
+			},
+			callback: commandSet,
+		},
 	}
 	validCommands["help"] = cliCommand{
 			name: "help",
 			description: "Displays a help message",
 			callback: func(conf *config, args ...string) error {
-				fmt.Println("Welcome to the Pokedex!")
-				fmt.Println("Usage:")
-				fmt.Println()
-				for _, v := range (validCommands) {
-					fmt.Println(fmt.Sprintf("%v: %v", v.name, v.description))
+				names := make([]string, 0, len(validCommands))
+				for name := range validCommands {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				doc := helpDoc{}
+				for _, name := range names {
+					v := validCommands[name]
+					doc.Commands = append(doc.Commands, commandDoc{Name: v.name, Usage: v.synopsis(), Description: v.description})
 				}
-				return nil
+				return conf.emit(doc, func() {
+					fmt.Fprintln(conf.Out, "Welcome to the Pokedex!")
+					fmt.Fprintln(conf.Out, "Usage:")
+					fmt.Fprintln(conf.Out)
+					for _, v := range doc.Commands {
+						fmt.Fprintln(conf.Out, fmt.Sprintf("%v: %v", v.Usage, v.Description))
+					}
+				})
 			},
 		}
-	for i := 0; ; i++ {
-		fmt.Print("Pokedex > ")
-		if scanner.Scan() {
-			usrinput := scanner.Text()
-			cleanedUsrinput := cleanInput(usrinput)
-			if len(cleanedUsrinput) > 0 {
-				command := cleanedUsrinput[0]
-				args := ""
+	return validCommands
+}
 
-				if len(cleanedUsrinput) > 1 {
-					args = cleanedUsrinput[1]
-				}
-				cmd, ok := validCommands[command]
-				if !ok {
-					fmt.Println("Unknown command")
-				} else {
-					if err := cmd.callback(&conf, args); err != nil {
-					fmt.Println(err)
-					}
-				}
+func main() {
+	defaultSavePath, err := save.DefaultPath()
+	if err != nil {
+		defaultSavePath = "pokedex-save.json"
+	}
+	baseURL := flag.String("base-url", pokeapi.DefaultBaseURL, "root of the PokeAPI to talk to")
+	savePath := flag.String("save", defaultSavePath, "path of the save file holding your pokedex, other trainers' saves go next to it")
+	profile := flag.String("profile", save.DefaultProfile, "the trainer to play as, see the profile command to create one")
+	defaultCacheDir := ""
+	if dir, err := os.UserCacheDir(); err == nil {
+		defaultCacheDir = filepath.Join(dir, "pokedex")
+	}
+	cacheDir := flag.String("cache-dir", defaultCacheDir, "directory for the on-disk response cache, empty disables it. only used with -http live")
+	cacheTTL := flag.Duration("cache-ttl", 7*24*time.Hour, "how long responses stay good in the on-disk cache")
+	httpMode := flag.String("http", string(fixture.Live), "live, record (save every response under -fixtures) or replay (serve only from -fixtures)")
+	fixturesDir := flag.String("fixtures", filepath.Join("testdata", "fixtures"), "directory holding recorded responses")
+	output := flag.String("output", outputText, "text, or json to print one JSON document per command")
+	versionGroup := flag.String("version-group", learnset.DefaultVersionGroup, "the games whose learnsets pokemon learn moves from, e.g. red-blue")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: pokedex [flags]                  start the interactive pokedex")
+		fmt.Fprintln(flag.CommandLine.Output(), "       pokedex [flags] <command> [args] run a single command and exit")
+		fmt.Fprintln(flag.CommandLine.Output(), "       pokedex [flags] run <script>     run the commands in a file, one per line")
+		fmt.Fprintln(flag.CommandLine.Output(), "commands can also be piped in on stdin")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	mode, err := fixture.ParseMode(*httpMode)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	outputMode, err := parseOutput(*output)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	// make a cache
+	// const duration := 5 * time.Millisecond
+	// cache := NewCache(duration)
+	// map the supported commands:
+	// pokemon payloads are big (sprites and moves), keep the memory tier bounded
+	cacheOpts := []pokecache.Option{pokecache.WithMaxBytes(64 << 20)}
+	// replay must only ever answer from fixtures and record has to see every
+	// request to save it, so the disk tier is only for live sessions
+	if *cacheDir != "" && mode == fixture.Live {
+		cacheOpts = append(cacheOpts, pokecache.WithDiskTier(*cacheDir, *cacheTTL))
+	}
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: fixture.NewTransport(mode, *fixturesDir, nil),
+	}
+	client := pokeapi.NewClient(*baseURL, httpClient, pokecache.NewCache(5 * time.Second, cacheOpts...))
+	conf := config{
+		Next: client.BaseURL() + "/location-area/",
+		Client: client,
+		SaveBase: *savePath,
+		Out: os.Stdout,
+		Rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+		SeenAreas: make(map[string]bool),
+		Output: outputMode,
+		VersionGroup: *versionGroup,
+	}
+	if err := switchProfile(&conf, *profile); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	validCommands := getCommands()
+	args := flag.Args()
+	switch {
+	case len(args) > 0 && args[0] == "run":
+		// pokedex run script.txt
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "usage: pokedex run <script>")
+			os.Exit(exitUsage)
+		}
+		f, err := os.Open(args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitError)
+		}
+		err = runScript(&conf, validCommands, f, args[1])
+		f.Close()
+		if code := exitCode(err); code != exitOK {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(code)
+		}
+	case len(args) > 0:
+		// pokedex <command> [args], the shell has already split and unquoted
+		// the args
+		tokens, raw := shellTokens(args)
+		err := runTokens(&conf, validCommands, tokens, raw)
+		if code := exitCode(err); code != exitOK {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(code)
+		}
+	default:
+		// the line editor gives us arrow keys, history and tab completion,
+		// the history lives next to the save file
+		editor := lineedit.New(os.Stdin, os.Stdout)
+		if stdinPiped() {
+			// commands piped in, run them like a script. a terminal the
+			// editor can't put in raw mode still gets the prompted repl
+			err := runScript(&conf, validCommands, os.Stdin, "stdin")
+			if code := exitCode(err); code != exitOK {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(code)
 			}
+			return
 		}
+		editor.Complete = completer(&conf, validCommands)
+		if err := editor.LoadHistory(filepath.Join(filepath.Dir(*savePath), "history")); err != nil {
+			fmt.Println(err)
+		}
+		repl(&conf, validCommands, editor)
 	}
-
 }
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+// output modes for commands
+const (
+	outputText = "text" // human readable, the default
+	outputJSON = "json" // one JSON document per command, on a single line
+)
+
+func parseOutput(s string) (string, error) {
+	switch s {
+	case outputText, outputJSON:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown output %q, expected text or json", s)
+}
+
+// prints doc as JSON when the output mode asks for it, otherwise leaves the
+// printing to text
+func (conf *config) emit(doc any, text func()) error {
+	if conf.Output != outputJSON {
+		text()
+		return nil
+	}
+	return json.NewEncoder(conf.Out).Encode(doc)
+}
+
+// named resources the way the API links to them
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type locationPageDoc struct {
+	Locations []namedResource `json:"locations"`
+	Next      string          `json:"next"`
+	Previous  string          `json:"previous"`
+}
+
+type encounterDoc struct {
+	Version  string `json:"version"`
+	Method   string `json:"method"`
+	Chance   int    `json:"chance"`
+	MinLevel int    `json:"min_level"`
+	MaxLevel int    `json:"max_level"`
+}
+
+type areaPokemonDoc struct {
+	namedResource
+	Encounters []encounterDoc `json:"encounters"`
+}
+
+type exploreDoc struct {
+	Area    string           `json:"area"`
+	Pokemon []areaPokemonDoc `json:"pokemon"`
+}
+
+func newExploreDoc(area pokeapi.PokemonInArea) exploreDoc {
+	doc := exploreDoc{Area: area.Name, Pokemon: []areaPokemonDoc{}}
+	for _, enc := range area.PokemonEncounters {
+		poke := areaPokemonDoc{
+			namedResource: namedResource{Name: enc.Pokemon.Name, URL: enc.Pokemon.URL},
+			Encounters:    []encounterDoc{},
+		}
+		for _, version := range enc.VersionDetails {
+			for _, detail := range version.EncounterDetails {
+				poke.Encounters = append(poke.Encounters, encounterDoc{
+					Version:  version.Version.Name,
+					Method:   detail.Method.Name,
+					Chance:   detail.Chance,
+					MinLevel: detail.MinLevel,
+					MaxLevel: detail.MaxLevel,
+				})
+			}
+		}
+		doc.Pokemon = append(doc.Pokemon, poke)
+	}
+	return doc
+}
+
+type catchDoc struct {
+	Pokemon string  `json:"pokemon"`
+	Level   int     `json:"level"`
+	Caught  bool    `json:"caught"`
+	Shakes  int     `json:"shakes"`
+	Chance  float64 `json:"chance"`
+	Ball    string  `json:"ball"`
+	Reward  int     `json:"reward"`
+	ID      int     `json:"id,omitempty"` // the caught pokemon's id in the collection
+	Shiny   bool    `json:"shiny,omitempty"`
+	Box     int     `json:"box,omitempty"` // the pc box it was sent to when the party was full
+	// how the lead pokemon grew from the catch
+	LevelUp *levelUpDoc `json:"level_up,omitempty"`
+	// the foe's turn when the catch failed in the middle of a battle
+	Battle *battleDoc `json:"battle,omitempty"`
+}
+
+type inspectDoc struct {
+	Name   string         `json:"name"`
+	Caught bool           `json:"caught"`
+	ID     int            `json:"id,omitempty"`
+	Height int            `json:"height,omitempty"`
+	Weight int            `json:"weight,omitempty"`
+	Stats  map[string]int `json:"stats,omitempty"`
+	Types  []string       `json:"types,omitempty"`
+	// the pokemon of this species the trainer has
+	Owned []collection.Owned `json:"owned,omitempty"`
+}
+
+// one owned pokemon along with its species data
+type ownedInspectDoc struct {
+	collection.Owned
+	InParty bool       `json:"in_party"`
+	Box     int        `json:"box,omitempty"` // the pc box it's in when not in the party
+	Pokemon inspectDoc `json:"pokemon"`
+}
+
+func newInspectDoc(pokemon pokeapi.Pokemon) inspectDoc {
+	doc := inspectDoc{
+		Name:   pokemon.Name,
+		Caught: true,
+		ID:     pokemon.ID,
+		Height: pokemon.Height,
+		Weight: pokemon.Weight,
+		Stats:  make(map[string]int),
+		Types:  []string{},
+	}
+	for _, stat := range pokemon.Stats {
+		doc.Stats[stat.Stat.Name] = stat.BaseStat
+	}
+	for _, poketype := range pokemon.Types {
+		doc.Types = append(doc.Types, poketype.Type.Name)
+	}
+	return doc
+}
+
+type pokedexDoc struct {
+	Pokemon []collection.Owned `json:"pokemon"`
+}
+
+type commandDoc struct {
+	Name        string `json:"name"`
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+}
+
+type helpDoc struct {
+	Commands []commandDoc `json:"commands"`
+}
+
+type messageDoc struct {
+	Message string `json:"message"`
+}
+
+type errorDoc struct {
+	Error string `json:"error"`
+}
+
+type settingDoc struct {
+	Setting string `json:"setting"`
+	Value   string `json:"value"`
+}
+
+// changes a session setting, the output mode or the version group moves are
+// learned from
+func commandSet(conf *config, args ...string) error {
+	switch args[0] {
+	case "output":
+		output, err := parseOutput(args[1])
+		if err != nil {
+			return err
+		}
+		conf.Output = output
+	case "version-group":
+		conf.VersionGroup = args[1]
+	default:
+		return fmt.Errorf("unknown setting %q, try output or version-group", args[0])
+	}
+	return conf.emit(settingDoc{Setting: args[0], Value: args[1]}, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v set to %v", args[0], args[1]))
+	})
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+type releaseDoc struct {
+	Released collection.Owned `json:"released"`
+}
+
+// nickname command renames one owned pokemon, no name clears the nickname
+func commandNickname(conf *config, args ...string) error {
+	owned, err := conf.Owned.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	name := ""
+	if len(args) > 1 {
+		name = args[1]
+	}
+	if err := owned.SetNickname(name); err != nil {
+		return err
+	}
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your pokedex: %w", err)
+	}
+
+	return conf.emit(*owned, func() {
+		if owned.Nickname == "" {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("#%v is just called %v again.", owned.ID, owned.Species))
+		} else {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("#%v %v is now called %v.", owned.ID, owned.Species, owned.Nickname))
+		}
+	})
+}
+
+// release command lets one owned pokemon go. the species stays in the
+// pokedex, only this pokemon is gone.
+func commandRelease(conf *config, args ...string) error {
+	if conf.Battle != nil {
+		return errInBattle
+	}
+	id, err := collection.ParseID(args[0])
+	if err != nil {
+		return err
+	}
+	released, err := conf.Owned.Release(id)
+	if err != nil {
+		return err
+	}
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your pokedex: %w", err)
+	}
+
+	return conf.emit(releaseDoc{Released: released}, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v was released back into the wild. Bye, %v!", released, released.Name()))
+	})
+}
+
+// the pokedex data for a species, fetched when it's missing like in old or
+// hand edited saves
+func speciesData(conf *config, name string) (pokeapi.Pokemon, error) {
+	if pokemon, ok := conf.Pokedex[name]; ok {
+		return pokemon, nil
+	}
+	pokemon, err := conf.Client.GetPokemon(name)
+	if err != nil {
+		return pokeapi.Pokemon{}, err
+	}
+	conf.Pokedex[name] = pokemon
+	return pokemon, nil
+}
+
+// ids of the pokemon the trainer owns
+func completeOwned(conf *config) []string {
+	return conf.Owned.IDs()
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/lulock/pokedex/internal/collection"
+)
+
+type partyDoc struct {
+	Pokemon []collection.Owned `json:"pokemon"` // the lead first
+}
+
+type boxDoc struct {
+	Box     int                `json:"box"`
+	Pokemon []collection.Owned `json:"pokemon"`
+}
+
+type storageDoc struct {
+	Pokemon collection.Owned `json:"pokemon"`
+	Box     int              `json:"box"` // where it went, 0 for the party
+}
+
+type swapDoc struct {
+	Swapped []collection.Owned `json:"swapped"`
+}
+
+// party command lists the pokemon the trainer carries, the lead first
+func commandParty(conf *config, args ...string) error {
+	doc := partyDoc{Pokemon: conf.Owned.InParty()}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("Your party (%v/%v):", len(doc.Pokemon), collection.PartySize))
+		printSlots(conf, doc.Pokemon)
+	})
+}
+
+// box command lists the pokemon stored in one pc box
+func commandBox(conf *config, args ...string) error {
+	n, err := parseBox(args[0])
+	if err != nil {
+		return err
+	}
+	stored, err := conf.Owned.Box(n)
+	if err != nil {
+		return err
+	}
+	doc := boxDoc{Box: n, Pokemon: stored}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("Box %v (%v/%v):", n, len(stored), collection.BoxSize))
+		printSlots(conf, stored)
+	})
+}
+
+// deposit command moves a party pokemon to a pc box, the first one with
+// room unless a box is given
+func commandDeposit(conf *config, args ...string) error {
+	if conf.Battle != nil {
+		return errInBattle
+	}
+	owned, err := conf.Owned.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	n := 0
+	if len(args) > 1 {
+		if n, err = parseBox(args[1]); err != nil {
+			return err
+		}
+	}
+	box, err := conf.Owned.Deposit(owned.ID, n)
+	if err != nil {
+		return err
+	}
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your pokedex: %w", err)
+	}
+
+	doc := storageDoc{Pokemon: *owned, Box: box}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v was deposited in box %v.", doc.Pokemon, box))
+	})
+}
+
+// withdraw command takes a pokemon out of its pc box and into the party
+func commandWithdraw(conf *config, args ...string) error {
+	if conf.Battle != nil {
+		return errInBattle
+	}
+	owned, err := conf.Owned.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	if err := conf.Owned.Withdraw(owned.ID); err != nil {
+		return err
+	}
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your pokedex: %w", err)
+	}
+
+	doc := storageDoc{Pokemon: *owned}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v joined your party.", doc.Pokemon))
+	})
+}
+
+// swap command exchanges the places of two pokemon, in the party or in
+// the boxes. it's how the party gets reordered and a new lead picked.
+func commandSwap(conf *config, args ...string) error {
+	if conf.Battle != nil {
+		return errInBattle
+	}
+	a, err := conf.Owned.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := conf.Owned.Lookup(args[1])
+	if err != nil {
+		return err
+	}
+	if err := conf.Owned.Swap(a.ID, b.ID); err != nil {
+		return err
+	}
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your pokedex: %w", err)
+	}
+
+	doc := swapDoc{Swapped: []collection.Owned{*a, *b}}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v and %v swapped places.", doc.Swapped[0], doc.Swapped[1]))
+		if lead := leadPokemon(conf); lead != nil && (lead.ID == a.ID || lead.ID == b.ID) {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("%v leads your party now.", lead.Name()))
+		}
+	})
+}
+
+func printSlots(conf *config, pokemon []collection.Owned) {
+	if len(pokemon) == 0 {
+		fmt.Fprintln(conf.Out, "  . empty")
+	}
+	for i, owned := range pokemon {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("  %v. %v", i+1, owned))
+	}
+}
+
+func parseBox(arg string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a box number", arg)
+	}
+	return n, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lulock/pokedex/internal/bag"
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/encounter"
+	"github.com/lulock/pokedex/internal/save"
+)
+
+func TestPartyAndBoxes(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	conf.SavePath = filepath.Join(t.TempDir(), "save.json")
+	conf.Owned.Add(collection.Owned{Species: "pikachu", Nickname: "Sparky", Level: 5})
+	conf.Owned.Add(collection.Owned{Species: "psyduck", Level: 3})
+
+	if err := runLine(conf, commands, "party"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Your party (2/6):", "  1. #1 Sparky (pikachu) Lv. 5", "  2. #2 psyduck Lv. 3")
+
+	if err := runLine(conf, commands, "deposit 2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "#2 psyduck Lv. 3 was deposited in box 1.")
+	if err := runLine(conf, commands, "box 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Box 1 (1/30):", "  1. #2 psyduck Lv. 3")
+	if err := runLine(conf, commands, "box 2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Box 2 (0/30):", "  . empty")
+	if err := runLine(conf, commands, "box two"); err == nil {
+		t.Errorf("expected an error for a box that isn't a number")
+	}
+
+	// swapping with the lead picks a new one
+	if err := runLine(conf, commands, "swap #1 #2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "#1 Sparky (pikachu) Lv. 5 and #2 psyduck Lv. 3 swapped places.", "psyduck leads your party now.")
+	if err := runLine(conf, commands, "withdraw 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "#1 Sparky (pikachu) Lv. 5 joined your party.")
+
+	saved, err := save.Load(conf.SavePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lead := saved.Owned.Lead(); lead == nil || lead.Species != "psyduck" || len(saved.Owned.Party) != 2 {
+		t.Errorf("expected the new party to be saved, got %+v", saved.Owned)
+	}
+
+	conf.Output = outputJSON
+	if err := runLine(conf, commands, "party"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, `{"pokemon":[{"id":2,"species":"psyduck","pokemon_id":0,"level":3,"experience":0,"caught_at":"0001-01-01T00:00:00Z"},{"id":1,"species":"pikachu","pokemon_id":0,"nickname":"Sparky","level":5,"experience":0,"caught_at":"0001-01-01T00:00:00Z"}]}`)
+}
+
+func TestCatchWithFullParty(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	for range collection.PartySize {
+		conf.Owned.Add(collection.Owned{Species: "magikarp", PokemonID: 129, Level: 50})
+	}
+	conf.Bag = bag.Bag{Items: map[string]int{"master-ball": 1}}
+	conf.Wild = &encounter.Wild{Pokemon: "psyduck", Level: 4}
+
+	if err := runLine(conf, commands, "catch psyduck --ball master-ball"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out,
+		"Throwing a master-ball at psyduck (Lv. 4)...",
+		"psyduck was caught!",
+		"It was added to your Pokedex as #7.",
+		"Your party is full, so it was sent to box 1.",
+		"You earned ₽64.",
+		"magikarp gained 36 Exp. Points!",
+	)
+}
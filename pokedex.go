@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
+
+// the base stats every pokemon has, in the order the api lists them
+var baseStats = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// generations the api knows about, generation-i to generation-ix
+var generations = []string{"i", "ii", "iii", "iv", "v", "vi", "vii", "viii", "ix"}
+
+// what the pokedex listing can be sorted by, besides the base stats
+var pokedexSorts = []string{"caught", "id", "name"}
+
+type pokedexEntry struct {
+	owned   collection.Owned
+	pokemon pokeapi.Pokemon
+}
+
+// reads a generation like 1, iv or generation-iv
+func parseGeneration(arg string) (string, error) {
+	name := strings.TrimPrefix(arg, "generation-")
+	if n, err := strconv.Atoi(name); err == nil && n >= 1 && n <= len(generations) {
+		name = generations[n-1]
+	}
+	if !slices.Contains(generations, name) {
+		return "", fmt.Errorf("%q is not a generation, try 1 to %v", arg, len(generations))
+	}
+	return "generation-" + name, nil
+}
+
+// pokedex command lists every owned pokemon, by default in the order they
+// were caught. a search keeps the ones whose species or nickname contains
+// it, the flags filter and sort the rest.
+func commandPokedex(conf *config, args ...string) error {
+	positional, flags := splitFlags(args)
+	search := ""
+	if len(positional) > 0 {
+		search = strings.ToLower(positional[0])
+	}
+	sortBy := flags["sort"]
+	if sortBy == "" {
+		sortBy = "caught"
+	}
+	if !slices.Contains(pokedexSorts, sortBy) && !slices.Contains(baseStats, sortBy) {
+		return fmt.Errorf("can't sort by %q, try %v or a base stat like speed", sortBy, strings.Join(pokedexSorts, ", "))
+	}
+	minimums := map[string]int{}
+	for _, stat := range baseStats {
+		value, ok := flags["min-"+stat]
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("--min-%v needs a number, got %q", stat, value)
+		}
+		minimums[stat] = n
+	}
+	generation := ""
+	if flags["generation"] != "" {
+		var err error
+		if generation, err = parseGeneration(flags["generation"]); err != nil {
+			return err
+		}
+	}
+
+	entries := []pokedexEntry{}
+	for _, owned := range conf.Owned.Pokemon {
+		if !strings.Contains(owned.Species, search) && !strings.Contains(strings.ToLower(owned.Nickname), search) {
+			continue
+		}
+		pokemon, err := speciesData(conf, owned.Species)
+		if err != nil {
+			return err
+		}
+		if flags["type"] != "" && !slices.Contains(pokemonTypes(pokemon), flags["type"]) {
+			continue
+		}
+		tooWeak := false
+		for stat, n := range minimums {
+			tooWeak = tooWeak || baseStat(pokemon, stat) < n
+		}
+		if tooWeak {
+			continue
+		}
+		if generation != "" {
+			// the generation lives on the species, which the client caches.
+			// forms like wormadam-plant aren't named after their species.
+			species, err := conf.Client.GetPokemonSpecies(pokemon.Species.Name)
+			if err != nil {
+				return err
+			}
+			if species.Generation.Name != generation {
+				continue
+			}
+		}
+		entries = append(entries, pokedexEntry{owned: owned, pokemon: pokemon})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch sortBy {
+		case "caught":
+			return a.owned.CaughtAt.Before(b.owned.CaughtAt)
+		case "id":
+			return a.owned.PokemonID < b.owned.PokemonID
+		case "name":
+			return a.owned.Species < b.owned.Species
+		}
+		// strongest first
+		return baseStat(a.pokemon, sortBy) > baseStat(b.pokemon, sortBy)
+	})
+	if flags["reverse"] != "" {
+		slices.Reverse(entries)
+	}
+
+	doc := pokedexDoc{Pokemon: []collection.Owned{}}
+	for _, entry := range entries {
+		doc.Pokemon = append(doc.Pokemon, entry.owned)
+	}
+	return conf.emit(doc, func() {
+		switch {
+		case len(conf.Owned.Pokemon) == 0:
+			fmt.Fprintln(conf.Out, "You haven't caught any Pokemon yet! Use the Catch command and try to catch 'em all.")
+		case len(entries) == 0:
+			fmt.Fprintln(conf.Out, "None of your Pokemon match.")
+		default:
+			fmt.Fprintln(conf.Out, "Your Pokedex:")
+			for _, entry := range entries {
+				if slices.Contains(baseStats, sortBy) {
+					fmt.Fprintln(conf.Out, fmt.Sprintf(" . %v (%v %v)", entry.owned, sortBy, baseStat(entry.pokemon, sortBy)))
+				} else {
+					fmt.Fprintln(conf.Out, fmt.Sprintf(" . %v", entry.owned))
+				}
+			}
+		}
+	})
+}
+
+// flags the pokedex command understands, true for the ones taking a value
+func pokedexFlags() map[string]bool {
+	flags := map[string]bool{"sort": true, "reverse": false, "type": true, "generation": true}
+	for _, stat := range baseStats {
+		flags["min-"+stat] = true
+	}
+	return flags
+}
+
+func completePokedexSorts(conf *config) []string {
+	return append(slices.Clone(pokedexSorts), baseStats...)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lulock/pokedex/internal/collection"
+)
+
+func TestPokedexFiltersAndSorts(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+	conf.Owned.Add(collection.Owned{Species: "psyduck", PokemonID: 54, Level: 10, CaughtAt: day(3)})
+	conf.Owned.Add(collection.Owned{Species: "pikachu", PokemonID: 25, Nickname: "Sparky", Level: 5, CaughtAt: day(1)})
+	conf.Owned.Add(collection.Owned{Species: "pichu", PokemonID: 172, Level: 2, CaughtAt: day(2)})
+	conf.Owned.Add(collection.Owned{Species: "gyarados", PokemonID: 130, Level: 30, CaughtAt: day(4)})
+
+	cases := []struct {
+		line     string
+		expected []string
+	}{
+		{
+			line:     "pokedex",
+			expected: []string{"Your Pokedex:", " . #2 Sparky (pikachu) Lv. 5", " . #3 pichu Lv. 2", " . #1 psyduck Lv. 10", " . #4 gyarados Lv. 30"},
+		},
+		{
+			line:     "pokedex --sort id",
+			expected: []string{"Your Pokedex:", " . #2 Sparky (pikachu) Lv. 5", " . #1 psyduck Lv. 10", " . #4 gyarados Lv. 30", " . #3 pichu Lv. 2"},
+		},
+		{
+			line:     "pokedex --sort name --reverse",
+			expected: []string{"Your Pokedex:", " . #1 psyduck Lv. 10", " . #2 Sparky (pikachu) Lv. 5", " . #3 pichu Lv. 2", " . #4 gyarados Lv. 30"},
+		},
+		{
+			line:     "pokedex --sort speed --type electric",
+			expected: []string{"Your Pokedex:", " . #2 Sparky (pikachu) Lv. 5 (speed 90)", " . #3 pichu Lv. 2 (speed 60)"},
+		},
+		{
+			line:     "pokedex --min-speed 81 --min-attack 100",
+			expected: []string{"Your Pokedex:", " . #4 gyarados Lv. 30"},
+		},
+		{
+			line:     "pokedex --generation 2",
+			expected: []string{"Your Pokedex:", " . #3 pichu Lv. 2"},
+		},
+		{
+			// the search looks at species and nicknames
+			line:     "pokedex spark",
+			expected: []string{"Your Pokedex:", " . #2 Sparky (pikachu) Lv. 5"},
+		},
+		{
+			line:     "pokedex pi --type water",
+			expected: []string{"None of your Pokemon match."},
+		},
+	}
+	for _, c := range cases {
+		if err := runLine(conf, commands, c.line); err != nil {
+			t.Errorf("%q: unexpected error: %v", c.line, err)
+			continue
+		}
+		expectLines(t, out, c.expected...)
+	}
+
+	for _, line := range []string{"pokedex --sort colour", "pokedex --min-speed fast", "pokedex --generation 10"} {
+		if err := runLine(conf, commands, line); err == nil {
+			t.Errorf("%q: expected an error", line)
+		}
+	}
+	// forms are filtered by the generation of their species
+	conf.Owned.Add(collection.Owned{Species: "wormadam-plant", PokemonID: 413, Level: 25, CaughtAt: day(5)})
+	if err := runLine(conf, commands, "pokedex --generation 4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Your Pokedex:", " . #5 wormadam-plant Lv. 25")
+}
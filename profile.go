@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lulock/pokedex/internal/save"
+)
+
+var errNoProfiles = errors.New("trainer profiles need a save file, start the pokedex with -save")
+
+type profileDoc struct {
+	Name     string     `json:"name"`
+	Current  bool       `json:"current"`
+	Species  int        `json:"species"`
+	Pokemon  int        `json:"pokemon"`
+	Money    int        `json:"money"`
+	Location string     `json:"location,omitempty"`
+	Stats    save.Stats `json:"stats"`
+}
+
+type profileListDoc struct {
+	Profiles []profileDoc `json:"profiles"`
+}
+
+func newProfileDoc(name string, f save.File) profileDoc {
+	return profileDoc{Name: name, Species: len(f.Pokedex), Pokemon: len(f.Owned.Pokemon), Money: f.Bag.Money, Location: f.Location, Stats: f.Stats}
+}
+
+// loads the named trainer's save into conf. only the default trainer can
+// start without being created first, so a typo never starts a new save.
+// whatever was in front of the previous trainer, a wild pokemon or a
+// battle, doesn't follow along.
+func switchProfile(conf *config, name string) error {
+	path, err := save.ProfilePath(conf.SaveBase, name)
+	if err != nil {
+		return err
+	}
+	exists, err := save.Exists(conf.SaveBase, name)
+	if err != nil {
+		return err
+	}
+	if !exists && name != save.DefaultProfile {
+		return fmt.Errorf("%w: %v, create it first with profile create %v", save.ErrNoProfile, name, name)
+	}
+	saved, err := save.Load(path)
+	if err != nil {
+		return err
+	}
+	conf.Profile = name
+	conf.SavePath = path
+	conf.Pokedex = saved.Pokedex
+	conf.Bag = saved.Bag
+	conf.Location = saved.Location
+	conf.Owned = saved.Owned
+	conf.Stats = saved.Stats
+	conf.Wild = nil
+	conf.Battle = nil
+	return nil
+}
+
+// profile command lists, creates, deletes and switches between trainers
+// sharing this pokedex, each with their own save
+func commandProfile(conf *config, args ...string) error {
+	if conf.SaveBase == "" {
+		return errNoProfiles
+	}
+	action := args[0]
+	if action == "list" {
+		if len(args) > 1 {
+			return fmt.Errorf("profile list doesn't take a name")
+		}
+		return listProfiles(conf)
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("profile %v needs a trainer name", action)
+	}
+	name := args[1]
+	exists, err := save.Exists(conf.SaveBase, name)
+	if err != nil {
+		return err
+	}
+
+	msg := ""
+	switch action {
+	case "create":
+		if exists || name == conf.Profile {
+			return fmt.Errorf("there's already a trainer called %v", name)
+		}
+		path, _ := save.ProfilePath(conf.SaveBase, name)
+		if err := save.Write(path, save.New()); err != nil {
+			return fmt.Errorf("could not create %v's save: %w", name, err)
+		}
+		msg = fmt.Sprintf("Created trainer %v, use profile switch %v to play as them.", name, name)
+	case "switch":
+		if conf.Battle != nil {
+			return errInBattle
+		}
+		if err := conf.save(); err != nil {
+			return fmt.Errorf("could not save your pokedex: %w", err)
+		}
+		if err := switchProfile(conf, name); err != nil {
+			return err
+		}
+		msg = fmt.Sprintf("Now playing as %v.", name)
+	case "delete":
+		if name == save.DefaultProfile {
+			return errors.New("the default trainer can't be deleted")
+		}
+		if name == conf.Profile {
+			return fmt.Errorf("you're playing as %v, switch to someone else first", name)
+		}
+		if !exists {
+			return fmt.Errorf("%w: %v", save.ErrNoProfile, name)
+		}
+		path, _ := save.ProfilePath(conf.SaveBase, name)
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		msg = fmt.Sprintf("Deleted trainer %v and everything they caught.", name)
+	default:
+		return fmt.Errorf("unknown profile action %q, try list, create, switch or delete", action)
+	}
+	return conf.emit(messageDoc{Message: msg}, func() {
+		fmt.Fprintln(conf.Out, msg)
+	})
+}
+
+func listProfiles(conf *config) error {
+	names, err := save.Profiles(conf.SaveBase)
+	if err != nil {
+		return err
+	}
+	doc := profileListDoc{Profiles: []profileDoc{}}
+	for _, name := range names {
+		if name == conf.Profile {
+			f := save.File{Pokedex: conf.Pokedex, Bag: conf.Bag, Location: conf.Location, Owned: conf.Owned, Stats: conf.Stats}
+			profile := newProfileDoc(name, f)
+			profile.Current = true
+			doc.Profiles = append(doc.Profiles, profile)
+			continue
+		}
+		path, _ := save.ProfilePath(conf.SaveBase, name)
+		f, err := save.Load(path)
+		if err != nil {
+			return err
+		}
+		doc.Profiles = append(doc.Profiles, newProfileDoc(name, f))
+	}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, "Trainers:")
+		for _, profile := range doc.Profiles {
+			mark := "."
+			if profile.Current {
+				mark = "*"
+			}
+			fmt.Fprintln(conf.Out, fmt.Sprintf(" %v %v: %v species, %v pokemon, ₽%v", mark, profile.Name, profile.Species, profile.Pokemon, profile.Money))
+			stats := profile.Stats
+			fmt.Fprintln(conf.Out, fmt.Sprintf("     caught %v, %v got away, won %v battles, lost %v, earned ₽%v", stats.Caught, stats.Escaped, stats.BattlesWon, stats.BattlesLost, stats.MoneyEarned))
+		}
+	})
+}
+
+// trainer names for profile switch and delete
+func completeProfiles(conf *config) []string {
+	actions := []string{"list", "create", "switch", "delete"}
+	if conf.SaveBase == "" {
+		return actions
+	}
+	names, err := save.Profiles(conf.SaveBase)
+	if err != nil {
+		return actions
+	}
+	return append(actions, names...)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/encounter"
+	"github.com/lulock/pokedex/internal/pokeapi"
+	"github.com/lulock/pokedex/internal/save"
+)
+
+func TestProfiles(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+
+	if err := runLine(conf, commands, "profile list"); !errors.Is(err, errNoProfiles) {
+		t.Errorf("expected errNoProfiles without a save, got %v", err)
+	}
+
+	conf.SaveBase = filepath.Join(t.TempDir(), "save.json")
+	if err := switchProfile(conf, save.DefaultProfile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conf.Pokedex["pikachu"] = pokeapi.Pokemon{Name: "pikachu"}
+	conf.Owned.Add(collection.Owned{Species: "pikachu", Level: 5})
+	conf.Stats = save.Stats{Caught: 3, Escaped: 1, BattlesWon: 2, MoneyEarned: 300}
+	conf.Wild = &encounter.Wild{Pokemon: "psyduck", Level: 3}
+
+	// -profile and profile switch both want the trainer created first
+	if err := runLine(conf, commands, "profile switch misty"); !errors.Is(err, save.ErrNoProfile) {
+		t.Errorf("expected ErrNoProfile, got %v", err)
+	}
+	if err := switchProfile(conf, "misty"); !errors.Is(err, save.ErrNoProfile) {
+		t.Errorf("expected ErrNoProfile, got %v", err)
+	}
+	if err := runLine(conf, commands, "profile create misty"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Created trainer misty, use profile switch misty to play as them.")
+	if err := runLine(conf, commands, "profile create misty"); err == nil {
+		t.Errorf("expected an error creating misty twice")
+	}
+	if err := runLine(conf, commands, "profile list"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out,
+		"Trainers:",
+		" * default: 1 species, 1 pokemon, ₽1000",
+		"     caught 3, 1 got away, won 2 battles, lost 0, earned ₽300",
+		" . misty: 0 species, 0 pokemon, ₽1000",
+		"     caught 0, 0 got away, won 0 battles, lost 0, earned ₽0",
+	)
+
+	// each trainer has their own pokedex, and the wild pokemon stays behind
+	if err := runLine(conf, commands, "profile switch misty"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Now playing as misty.")
+	if len(conf.Pokedex) != 0 || len(conf.Owned.Pokemon) != 0 || conf.Stats.Caught != 0 || conf.Wild != nil {
+		t.Errorf("expected a fresh start for misty, got %v %+v %v", conf.Pokedex, conf.Owned, conf.Wild)
+	}
+	if err := runLine(conf, commands, "profile delete misty"); err == nil {
+		t.Errorf("expected an error deleting the trainer playing")
+	}
+	if err := runLine(conf, commands, "profile switch default"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Now playing as default.")
+	if _, ok := conf.Pokedex["pikachu"]; !ok || len(conf.Owned.Pokemon) != 1 || conf.Stats.BattlesWon != 2 {
+		t.Errorf("expected the default trainer's pokedex back, got %v %+v", conf.Pokedex, conf.Owned)
+	}
+
+	if err := runLine(conf, commands, "profile delete misty"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Deleted trainer misty and everything they caught.")
+	if err := runLine(conf, commands, "profile delete default"); err == nil {
+		t.Errorf("expected an error deleting the default trainer")
+	}
+	if err := runLine(conf, commands, "profile switch"); err == nil {
+		t.Errorf("expected an error without a trainer name")
+	}
+	if err := runLine(conf, commands, "profile fly ash"); err == nil {
+		t.Errorf("expected an error for an unknown action")
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/evolution"
+	"github.com/lulock/pokedex/internal/growth"
+	"github.com/lulock/pokedex/internal/learnset"
+)
+
+type learnedMoveDoc struct {
+	Move   string `json:"move"`
+	Forgot string `json:"forgot,omitempty"`
+}
+
+type levelUpDoc struct {
+	ID         int              `json:"id"`
+	Pokemon    string           `json:"pokemon"` // the nickname when there is one
+	Species    string           `json:"species"`
+	Gained     int              `json:"gained"`
+	Experience int              `json:"experience"`
+	From       int              `json:"from"`
+	Level      int              `json:"level"`
+	Learned    []learnedMoveDoc `json:"learned"`
+	Evolution  *evolutionDoc    `json:"evolution,omitempty"`
+}
+
+type evolutionDoc struct {
+	Into    string           `json:"into"`
+	Learned []learnedMoveDoc `json:"learned"` // moves the new species learns right away
+}
+
+// the pokemon that earns experience and battles, the first one in the party
+func leadPokemon(conf *config) *collection.Owned {
+	return conf.Owned.Lead()
+}
+
+// gives owned experience and works out what comes with it: new levels,
+// new moves for the version group we're playing and maybe an evolution
+func gainExperience(conf *config, owned *collection.Owned, gained int) (levelUpDoc, error) {
+	doc := levelUpDoc{ID: owned.ID, Pokemon: owned.Name(), Species: owned.Species, Gained: gained, From: owned.Level, Learned: []learnedMoveDoc{}}
+	// owned.Species is the pokemon's name, which for forms like
+	// wormadam-plant isn't the name of the species
+	pokemon, err := speciesData(conf, owned.Species)
+	if err != nil {
+		return doc, err
+	}
+	species, err := conf.Client.GetPokemonSpecies(pokemon.Species.Name)
+	if err != nil {
+		return doc, err
+	}
+	rate := species.GrowthRate.Name
+	floor, err := growth.Experience(rate, owned.Level)
+	if err != nil {
+		return doc, err
+	}
+	// pokemon from older saves have no experience yet, they start at their level
+	owned.Experience = max(owned.Experience, floor) + gained
+	level, err := growth.Level(rate, owned.Experience)
+	if err != nil {
+		return doc, err
+	}
+	doc.Experience = owned.Experience
+	doc.Level = max(level, owned.Level)
+	if level <= owned.Level {
+		return doc, nil
+	}
+
+	doc.Learned = learnMoves(owned, learnset.Between(learnset.LevelUp(pokemon, conf.VersionGroup), owned.Level, level))
+	owned.Level = level
+
+	if species.EvolutionChain.URL == "" {
+		return doc, nil
+	}
+	chain, err := conf.Client.GetEvolutionChain(species.EvolutionChain.URL)
+	if err != nil {
+		return doc, err
+	}
+	candidate := evolution.Candidate{Species: species.Name, Level: owned.Level, Moves: owned.Moves, Time: time.Now()}
+	next, ok := evolution.Next(chain.Chain, candidate)
+	if !ok {
+		return doc, nil
+	}
+	// the chain names species, evolving turns into the species' default form
+	nextSpecies, err := conf.Client.GetPokemonSpecies(next)
+	if err != nil {
+		return doc, err
+	}
+	evolved, err := speciesData(conf, nextSpecies.DefaultPokemon())
+	if err != nil {
+		return doc, err
+	}
+	owned.Species = evolved.Name
+	owned.PokemonID = evolved.ID
+	// the evolved species may learn something right away at this level
+	doc.Evolution = &evolutionDoc{
+		Into:    evolved.Name,
+		Learned: learnMoves(owned, learnset.Between(learnset.LevelUp(evolved, conf.VersionGroup), owned.Level-1, owned.Level)),
+	}
+	return doc, nil
+}
+
+// teaches owned each move in turn, forgetting the oldest when it's full
+func learnMoves(owned *collection.Owned, moves []string) []learnedMoveDoc {
+	learned := []learnedMoveDoc{}
+	for _, move := range moves {
+		if forgot, ok := owned.Learn(move); ok {
+			learned = append(learned, learnedMoveDoc{Move: move, Forgot: forgot})
+		}
+	}
+	return learned
+}
+
+// the experience and moves a freshly caught pokemon starts out with
+func startingProgress(conf *config, owned *collection.Owned, growthRate string) error {
+	exp, err := growth.Experience(growthRate, owned.Level)
+	if err != nil {
+		return err
+	}
+	owned.Experience = exp
+	pokemon, err := speciesData(conf, owned.Species)
+	if err != nil {
+		return err
+	}
+	learnMoves(owned, learnset.Between(learnset.LevelUp(pokemon, conf.VersionGroup), 0, owned.Level))
+	return nil
+}
+
+func printLevelUp(conf *config, doc levelUpDoc) {
+	fmt.Fprintln(conf.Out, fmt.Sprintf("%v gained %v Exp. Points!", doc.Pokemon, doc.Gained))
+	if doc.Level > doc.From {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v grew to Lv. %v!", doc.Pokemon, doc.Level))
+	}
+	printLearned(conf, doc.Pokemon, doc.Learned)
+	if doc.Evolution != nil {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("What? %v is evolving!", doc.Pokemon))
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v evolved into %v!", doc.Pokemon, doc.Evolution.Into))
+		name := doc.Pokemon
+		if name == doc.Species {
+			name = doc.Evolution.Into
+		}
+		printLearned(conf, name, doc.Evolution.Learned)
+	}
+}
+
+func printLearned(conf *config, name string, learned []learnedMoveDoc) {
+	for _, move := range learned {
+		if move.Forgot != "" {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("%v forgot %v and learned %v!", name, move.Forgot, move.Move))
+		} else {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("%v learned %v!", name, move.Move))
+		}
+	}
+}
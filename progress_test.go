@@ -0,0 +1,162 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lulock/pokedex/internal/bag"
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/encounter"
+	"github.com/lulock/pokedex/internal/save"
+)
+
+func TestGainExperienceEvolves(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	magikarp, _ := conf.Owned.Add(collection.Owned{Species: "magikarp", PokemonID: 129, Nickname: "Goldie", Level: 14, Moves: []string{"splash"}})
+	owned, _ := conf.Owned.Get(magikarp.ID)
+
+	// 14^3 to 20^3 on the medium curve
+	doc, err := gainExperience(conf, owned, 8000-2744)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owned.Level != 20 || owned.Experience != 8000 || owned.Species != "gyarados" || owned.PokemonID != 130 {
+		t.Errorf("unexpected pokemon after leveling: %+v", owned)
+	}
+	if expected := []string{"splash", "tackle", "dragon-rage"}; !slices.Equal(owned.Moves, expected) {
+		t.Errorf("Expected: %v, but got %v.", expected, owned.Moves)
+	}
+	if _, ok := conf.Pokedex["gyarados"]; !ok {
+		t.Errorf("expected gyarados to be registered in the pokedex")
+	}
+
+	printLevelUp(conf, doc)
+	expectLines(t, out,
+		"Goldie gained 5256 Exp. Points!",
+		"Goldie grew to Lv. 20!",
+		"Goldie learned tackle!",
+		"What? Goldie is evolving!",
+		"Goldie evolved into gyarados!",
+		"Goldie learned dragon-rage!",
+	)
+
+	// not enough for another level
+	doc, err = gainExperience(conf, owned, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.Level != 20 || doc.Evolution != nil || len(doc.Learned) != 0 || owned.Experience != 8010 {
+		t.Errorf("unexpected level up: %+v", doc)
+	}
+}
+
+func TestGainExperienceNeedsConditions(t *testing.T) {
+	conf, _, _ := newTestConfig(t)
+	conf.Owned.Add(collection.Owned{Species: "pikachu", PokemonID: 25, Level: 4})
+	owned, _ := conf.Owned.Get(1)
+
+	// raichu needs a thunder stone, so pikachu only grows
+	doc, err := gainExperience(conf, owned, 100000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owned.Species != "pikachu" || doc.Evolution != nil || owned.Level != 46 {
+		t.Errorf("unexpected pokemon after leveling: %+v", owned)
+	}
+	if expected := []string{"tail-whip", "thunder-wave", "quick-attack"}; !slices.Equal(owned.Moves, expected) {
+		t.Errorf("Expected: %v, but got %v.", expected, owned.Moves)
+	}
+	if len(doc.Learned) != 3 || doc.Learned[0].Move != "tail-whip" {
+		t.Errorf("unexpected moves learned: %+v", doc.Learned)
+	}
+}
+
+func TestCatchGivesExperience(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	conf.Bag = bag.Bag{Items: map[string]int{"master-ball": 1}}
+	conf.Location = "eterna-city-area"
+	conf.Wild = &encounter.Wild{Pokemon: "psyduck", Level: 5}
+	conf.Owned.Add(collection.Owned{Species: "pikachu", PokemonID: 25, Nickname: "Sparky", Level: 4})
+
+	if err := runLine(conf, getCommands(), "catch --ball master-ball"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out,
+		"Throwing a master-ball at psyduck (Lv. 5)...",
+		"psyduck was caught!",
+		"It was added to your Pokedex as #2.",
+		"You earned ₽64.",
+		"Sparky gained 45 Exp. Points!",
+	)
+
+	psyduck, err := conf.Owned.Get(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if psyduck.Experience != 125 || !slices.Equal(psyduck.Moves, []string{"water-sport", "scratch", "tail-whip"}) {
+		t.Errorf("unexpected starting progress: %+v", psyduck)
+	}
+	if lead, _ := conf.Owned.Get(1); lead.Experience != 64+45 {
+		t.Errorf("expected the lead to get the experience, got %+v", lead)
+	}
+}
+
+func TestGainExperienceForms(t *testing.T) {
+	conf, _, _ := newTestConfig(t)
+	burmy, _ := conf.Owned.Add(collection.Owned{Species: "burmy", PokemonID: 412, Level: 19, Moves: []string{"protect", "tackle"}})
+	owned, _ := conf.Owned.Get(burmy.ID)
+
+	// the chain says wormadam, which is only served as its forms
+	doc, err := gainExperience(conf, owned, 8000-6859)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owned.Species != "wormadam-plant" || owned.PokemonID != 413 || doc.Evolution == nil || doc.Evolution.Into != "wormadam-plant" {
+		t.Errorf("expected burmy to evolve into its default form, got %+v", owned)
+	}
+
+	// and the form can keep growing even though it isn't a species name
+	doc, err = gainExperience(conf, owned, 9261-8000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owned.Level != 21 || doc.Evolution != nil {
+		t.Errorf("unexpected pokemon after leveling: %+v", owned)
+	}
+}
+
+func TestCatchKeptWhenExperienceFails(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	conf.SavePath = filepath.Join(t.TempDir(), "save.json")
+	conf.Bag = bag.Bag{Items: map[string]int{"master-ball": 1}}
+	conf.Location = "eterna-city-area"
+	conf.Wild = &encounter.Wild{Pokemon: "psyduck", Level: 5}
+	// the api doesn't know this one, so looking up its species fails
+	conf.Owned.Add(collection.Owned{Species: "missingno", PokemonID: 0, Nickname: "Glitch", Level: 4})
+
+	err := runLine(conf, getCommands(), "catch --ball master-ball")
+	if err == nil || !strings.HasPrefix(err.Error(), "Glitch couldn't gain experience") {
+		t.Fatalf("expected the experience to fail, got %v", err)
+	}
+	expectLines(t, out,
+		"Throwing a master-ball at psyduck (Lv. 5)...",
+		"psyduck was caught!",
+		"It was added to your Pokedex as #2.",
+		"You earned ₽64.",
+	)
+	if psyduck, err := conf.Owned.Get(2); err != nil || psyduck.Species != "psyduck" {
+		t.Errorf("expected psyduck to be kept, got %+v, %v", psyduck, err)
+	}
+	if conf.Bag.Money != 64 || conf.Stats.Caught != 1 || conf.Wild != nil {
+		t.Errorf("expected the catch to count, got money %v, stats %+v", conf.Bag.Money, conf.Stats)
+	}
+	saved, err := save.Load(conf.SavePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(saved.Owned.Pokemon) != 2 {
+		t.Errorf("expected the catch to be saved, got %+v", saved.Owned)
+	}
+}
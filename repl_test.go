@@ -1,6 +1,16 @@
 package main
 
-import "testing"
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/lineedit"
+	"github.com/lulock/pokedex/internal/pokeapi"
+)
 
 func TestCleanInput(t *testing.T) {
 	// start by creating a slice of test case structs
@@ -44,3 +54,241 @@ func TestCleanInput(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenize(t *testing.T) {
+	cases := []struct{
+		input string
+		expected []string
+	}{
+		{
+			input: "  Catch   PIKACHU ",
+			expected: []string{"catch", "pikachu"},
+		},
+		{
+			input: `nickname 3 "Sir Sparks"`,
+			expected: []string{"nickname", "3", "Sir Sparks"},
+		},
+		{
+			input: `nickname 3 'it''s "me"'`,
+			expected: []string{"nickname", "3", `its "me"`},
+		},
+		{
+			input: `pokedex --type=Fire --name="Mr \"Mime\""`,
+			expected: []string{"pokedex", "--type=fire", `--name=Mr "Mime"`},
+		},
+		{
+			input: `inspect ""`,
+			expected: []string{"inspect", ""},
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := tokenize(c.input)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", c.input, err)
+			continue
+		}
+		if len(actual) != len(c.expected) {
+			t.Errorf("Expected: %q, but got %q.", c.expected, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != c.expected[i] {
+				t.Errorf("Expected: %q, but got: %q.", c.expected[i], actual[i])
+			}
+		}
+	}
+
+	if _, err := tokenize(`catch "pikachu`); err == nil {
+		t.Errorf("expected an error for an unterminated quote")
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	cmd := cliCommand{
+		name: "pokedex",
+		maxArgs: 1,
+		flags: map[string]bool{"type": true, "reverse": false},
+	}
+	cases := []struct{
+		input []string
+		expected []string
+		wantErr bool
+	}{
+		{
+			input: []string{"--type", "fire", "char", "--reverse"},
+			expected: []string{"--type=fire", "char", "--reverse"},
+		},
+		{
+			input: []string{"--", "--reverse"},
+			expected: []string{"--", "--reverse"},
+		},
+		{input: []string{"a", "b"}, wantErr: true},
+		{input: []string{"--type"}, wantErr: true},
+		{input: []string{"--reverse=yes"}, wantErr: true},
+		{input: []string{"--colour=red"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		actual, err := cmd.parseArgs(c.input, c.input)
+		if c.wantErr {
+			if _, ok := err.(usageError); !ok {
+				t.Errorf("expected a usage error for %q, got %v", c.input, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", c.input, err)
+			continue
+		}
+		if strings.Join(actual, " ") != strings.Join(c.expected, " ") {
+			t.Errorf("Expected: %q, but got %q.", c.expected, actual)
+		}
+	}
+
+	positional, flags := splitFlags([]string{"--type=fire", "char", "--reverse", "--", "--x"})
+	if strings.Join(positional, " ") != "char --x" || flags["type"] != "fire" || flags["reverse"] != "true" {
+		t.Errorf("unexpected split: %q %v", positional, flags)
+	}
+}
+
+func TestRunLineArity(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+
+	err := runLine(conf, commands, "explore")
+	if _, ok := err.(usageError); !ok {
+		t.Fatalf("expected a usage error, got %v", err)
+	}
+	if err.Error() != "not enough arguments\nusage: explore <area>" {
+		t.Errorf("unexpected message: %q", err.Error())
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected explore not to run, got %q", out.String())
+	}
+
+	if err := runLine(conf, commands, "map please"); err == nil {
+		t.Errorf("expected map to reject arguments")
+	}
+	if err := runLine(conf, commands, "fly"); err == nil || err.Error() != "Unknown command" {
+		t.Errorf("expected an unknown command error, got %v", err)
+	}
+	if err := runLine(conf, commands, "   "); err != nil {
+		t.Errorf("expected a blank line to do nothing, got %v", err)
+	}
+}
+
+func TestCompleter(t *testing.T) {
+	conf, _, _ := newTestConfig(t)
+	complete := completer(conf, getCommands())
+
+	if err := commandMap(conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := commandExplore(conf, "canalave-city-area"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conf.Pokedex["psyduck"] = pokeapi.Pokemon{Name: "psyduck"}
+
+	cases := []struct{
+		before string
+		expected []string
+	}{
+		{before: "explore ", expected: []string{"canalave-city-area", "eterna-city-area"}},
+		{before: "catch p", expected: []string{"tentacool", "pikachu"}},
+		{before: "inspect ", expected: []string{"psyduck"}},
+		{before: "map ", expected: nil},
+		{before: "catch pikachu --ball ", expected: []string{"poke-ball"}},
+		{before: "catch pikachu --ball p", expected: []string{"poke-ball"}},
+	}
+	for _, c := range cases {
+		actual := complete(c.before)
+		if strings.Join(actual, " ") != strings.Join(c.expected, " ") {
+			t.Errorf("%q: Expected: %q, but got %q.", c.before, c.expected, actual)
+		}
+	}
+
+	names := complete("ex")
+	if !slices.Contains(names, "explore") || !slices.Contains(names, "exit") {
+		t.Errorf("expected command names, got %q", names)
+	}
+}
+
+func TestRunScript(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+
+	script := "# warm up\nmap\n\nexplore eterna-city-area\nexit\nmap\n"
+	err := runScript(conf, commands, strings.NewReader(script), "test.txt")
+	if !errors.Is(err, errExit) || exitCode(err) != exitOK {
+		t.Fatalf("expected the script to stop at exit, got %v", err)
+	}
+	expectLines(t, out,
+		"canalave-city-area",
+		"eterna-city-area",
+		"Looking around eterna-city-area for pokemon 🧐",
+		"Found these fellas:",
+		". psyduck",
+		"Closing the Pokedex... Goodbye!",
+	)
+
+	err = runScript(conf, commands, strings.NewReader("map\nexplore nowhere\nmap\n"), "test.txt")
+	if exitCode(err) != exitError || !strings.HasPrefix(err.Error(), "test.txt:2: ") {
+		t.Errorf("expected the script to fail on line 2, got %v", err)
+	}
+
+	err = runScript(conf, commands, strings.NewReader("inspect\n"), "test.txt")
+	if exitCode(err) != exitUsage {
+		t.Errorf("expected a usage exit code, got %v", err)
+	}
+	err = runScript(conf, commands, strings.NewReader("fly\n"), "test.txt")
+	if exitCode(err) != exitUsage {
+		t.Errorf("expected a usage exit code, got %v", err)
+	}
+	if err := runScript(conf, commands, strings.NewReader(""), "test.txt"); err != nil {
+		t.Errorf("expected an empty script to succeed, got %v", err)
+	}
+}
+
+func TestShellTokens(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+
+	// one-shot args are normalised like a line typed in the repl
+	tokens, raw := shellTokens([]string{"Explore", "Eterna-City-Area"})
+	if err := runTokens(conf, commands, tokens, raw); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Looking around eterna-city-area for pokemon 🧐", "Found these fellas:", ". psyduck")
+
+	conf.Owned.Add(collection.Owned{Species: "psyduck", PokemonID: 54, Level: 3})
+	tokens, raw = shellTokens([]string{"pokedex", "--type", "Water"})
+	if err := runTokens(conf, commands, tokens, raw); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Your Pokedex:", " . #1 psyduck Lv. 3")
+
+	// nicknames keep their case either way
+	tokens, raw = shellTokens([]string{"nickname", "1", "Quackers"})
+	if err := runTokens(conf, commands, tokens, raw); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "#1 psyduck is now called Quackers.")
+	if err := runLine(conf, commands, "nickname 1 Ducky"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "#1 psyduck is now called Ducky.")
+}
+
+func TestReplErrorsInJSON(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	editor := lineedit.New(strings.NewReader("fly\nset output json\nfly\nexit\n"), io.Discard)
+
+	repl(conf, getCommands(), editor)
+	expectLines(t, out,
+		"Unknown command",
+		`{"setting":"output","value":"json"}`,
+		`{"error":"Unknown command"}`,
+		`{"message":"Closing the Pokedex... Goodbye!"}`,
+	)
+}
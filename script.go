@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lulock/pokedex/internal/lineedit"
+)
+
+// returned by the exit command to end the session
+var errExit = errors.New("exit")
+
+// exit codes for scripted use
+const (
+	exitOK    = 0
+	exitError = 1 // a command failed
+	exitUsage = 2 // unknown command or bad arguments
+)
+
+// maps the error from running a command to the process exit code
+func exitCode(err error) int {
+	var usage usageError
+	switch {
+	case err == nil, errors.Is(err, errExit):
+		return exitOK
+	case errors.As(err, &usage), errors.Is(err, errUnknownCommand):
+		return exitUsage
+	}
+	return exitError
+}
+
+// runs the commands in r one line at a time, skipping blank lines and
+// # comments. it stops at the first failing command and reports which line
+// it was on. name is used in that report, e.g. the script's file name.
+func runScript(conf *config, commands map[string]cliCommand, r io.Reader, name string) error {
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := runLine(conf, commands, line); err != nil {
+			if errors.Is(err, errExit) {
+				return err
+			}
+			return scriptError{name: name, line: lineNo, err: err}
+		}
+	}
+	return scanner.Err()
+}
+
+// scriptError says where in a script a command failed
+type scriptError struct {
+	name string
+	line int
+	err  error
+}
+
+func (e scriptError) Error() string {
+	return fmt.Sprintf("%v:%v: %v", e.name, e.line, e.err)
+}
+
+func (e scriptError) Unwrap() error {
+	return e.err
+}
+
+// the interactive loop, it returns once the user exits or input runs out
+func repl(conf *config, commands map[string]cliCommand, editor *lineedit.Editor) {
+	for {
+		line, err := editor.ReadLine("Pokedex > ")
+		if errors.Is(err, lineedit.ErrInterrupted) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			commandExit(conf)
+			return
+		}
+		if err != nil {
+			// history failing to save shouldn't stop the command from running
+			printError(conf, err)
+		}
+		err = runLine(conf, commands, line)
+		if errors.Is(err, errExit) {
+			return
+		}
+		if err != nil {
+			printError(conf, err)
+		}
+	}
+}
+
+// errors are documents too in json mode, so every line of output stays json
+func printError(conf *config, err error) {
+	conf.emit(errorDoc{Error: err.Error()}, func() {
+		fmt.Fprintln(conf.Out, err)
+	})
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/transfer"
+)
+
+var errAlreadyImported = errors.New("that pokemon has already arrived, a transfer file can only be imported once")
+
+type exportDoc struct {
+	Pokemon collection.Owned `json:"pokemon"`
+	File    string           `json:"file"`
+}
+
+type importDoc struct {
+	Pokemon collection.Owned `json:"pokemon"`
+	File    string           `json:"file"`
+	Trainer string           `json:"trainer,omitempty"` // who exported it
+	Box     int              `json:"box,omitempty"`     // the pc box it was sent to when the party was full
+}
+
+// export command sends one owned pokemon away into a transfer file that
+// another trainer can import. it leaves this save, like a trade would.
+func commandExport(conf *config, args ...string) error {
+	if conf.Battle != nil {
+		return errInBattle
+	}
+	owned, err := conf.Owned.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	// check before writing, a file left behind would be a copy
+	if err := conf.Owned.Releasable(owned.ID); err != nil {
+		return err
+	}
+	path := args[1]
+	species, err := speciesData(conf, owned.Species)
+	if err != nil {
+		return err
+	}
+	data, err := transfer.Encode(transfer.New(conf.Profile, *owned, species))
+	if err != nil {
+		return err
+	}
+	if err := writeNewFile(path, data); err != nil {
+		return err
+	}
+	exported, err := conf.Owned.Release(owned.ID)
+	if err != nil {
+		return err
+	}
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your pokedex: %w", err)
+	}
+
+	return conf.emit(exportDoc{Pokemon: exported, File: path}, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v was exported to %v. Take good care, %v!", exported, path, exported.Name()))
+	})
+}
+
+// writeNewFile writes data to path, which mustn't exist yet. creating it
+// exclusively means a file that appears in the meantime isn't overwritten.
+func writeNewFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%v already exists, pick another file", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// import command takes in a pokemon from a transfer file. it gets a new id
+// here and joins the party, or a pc box when the party is full.
+func commandImport(conf *config, args ...string) error {
+	if conf.Battle != nil {
+		return errInBattle
+	}
+	path := args[0]
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	f, err := transfer.Decode(data)
+	if err != nil {
+		return fmt.Errorf("could not import %v: %w", path, err)
+	}
+	if slices.Contains(conf.Owned.Imported, f.Checksum) {
+		return fmt.Errorf("could not import %v: %w", path, errAlreadyImported)
+	}
+	if _, ok := conf.Pokedex[f.Species.Name]; !ok {
+		conf.Pokedex[f.Species.Name] = f.Species
+	}
+	imported, box := conf.Owned.Add(f.Pokemon)
+	conf.Owned.Imported = append(conf.Owned.Imported, f.Checksum)
+	if err := conf.save(); err != nil {
+		return fmt.Errorf("could not save your pokedex: %w", err)
+	}
+
+	doc := importDoc{Pokemon: imported, File: path, Trainer: f.Trainer, Box: box}
+	return conf.emit(doc, func() {
+		if f.Trainer != "" {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("%v arrived from %v!", imported.Name(), f.Trainer))
+		} else {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("%v arrived!", imported.Name()))
+		}
+		fmt.Fprintln(conf.Out, fmt.Sprintf("It was added to your Pokedex as %v.", imported))
+		if box != 0 {
+			fmt.Fprintln(conf.Out, fmt.Sprintf("Your party is full, so it was sent to box %v.", box))
+		}
+	})
+}
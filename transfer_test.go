@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lulock/pokedex/internal/collection"
+	"github.com/lulock/pokedex/internal/transfer"
+)
+
+func TestExportImport(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+	dir := t.TempDir()
+	conf.Profile = "ash"
+	conf.Owned.Add(collection.Owned{Species: "pikachu", PokemonID: 25, Nickname: "Sparky", Level: 12, Moves: []string{"thunder-shock"}})
+	conf.Owned.Add(collection.Owned{Species: "psyduck", PokemonID: 54, Level: 3})
+	// paths keep their case without quotes, the temp dir has capitals in it too
+	path := filepath.Join(dir, "Sparky.json")
+
+	// files that are already there are left alone
+	taken := filepath.Join(dir, "Taken.json")
+	os.WriteFile(taken, []byte("keep me"), 0o644)
+	if err := runLine(conf, commands, "export 2 "+taken); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected an error exporting over an existing file, got %v", err)
+	}
+	if data, _ := os.ReadFile(taken); string(data) != "keep me" {
+		t.Errorf("expected the existing file to be untouched, got %q", data)
+	}
+	if _, err := conf.Owned.Get(2); err != nil {
+		t.Errorf("expected psyduck to stay, got %v", err)
+	}
+
+	if err := runLine(conf, commands, "export 1 "+path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "#1 Sparky (pikachu) Lv. 12 was exported to "+path+". Take good care, Sparky!")
+	if _, err := conf.Owned.Get(1); !errors.Is(err, collection.ErrNotOwned) {
+		t.Errorf("expected the exported pokemon to leave the save, got %v", err)
+	}
+	// psyduck is all that's left in the party, it can't leave
+	lonely := filepath.Join(dir, "Psyduck.json")
+	if err := runLine(conf, commands, "export 2 "+lonely); !errors.Is(err, collection.ErrLastInParty) {
+		t.Errorf("expected ErrLastInParty, got %v", err)
+	}
+	if _, err := os.Stat(lonely); err == nil {
+		t.Errorf("expected no file for a pokemon that couldn't be exported")
+	}
+
+	// another trainer takes it in with a new id
+	other, out, _ := newTestConfig(t)
+	other.Owned.Add(collection.Owned{Species: "magikarp", Level: 5})
+	if err := runLine(other, commands, "import "+path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, "Sparky arrived from ash!", "It was added to your Pokedex as #2 Sparky (pikachu) Lv. 12.")
+	imported, err := other.Owned.Get(2)
+	if err != nil || strings.Join(imported.Moves, ",") != "thunder-shock" {
+		t.Errorf("expected Sparky to keep its moves, got %+v %v", imported, err)
+	}
+	if _, ok := other.Pokedex["pikachu"]; !ok {
+		t.Errorf("expected the species to be registered")
+	}
+
+	// the same file can't clone it
+	if err := runLine(other, commands, "import "+path); !errors.Is(err, errAlreadyImported) {
+		t.Errorf("expected errAlreadyImported, got %v", err)
+	}
+	if n := len(other.Owned.OfSpecies("pikachu")); n != 1 {
+		t.Errorf("expected one pikachu after importing twice, got %v", n)
+	}
+
+	// hand edited files are turned away
+	data, _ := os.ReadFile(path)
+	edited := filepath.Join(dir, "Edited.json")
+	os.WriteFile(edited, []byte(strings.Replace(string(data), `"level": 12`, `"level": 100`, 1)), 0o644)
+	if err := runLine(other, commands, "import "+edited); !errors.Is(err, transfer.ErrChecksum) {
+		t.Errorf("expected ErrChecksum, got %v", err)
+	}
+}
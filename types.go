@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lulock/pokedex/internal/pokeapi"
+	"github.com/lulock/pokedex/internal/typechart"
+)
+
+type typeMatchupDoc struct {
+	Type       string  `json:"type"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+type typesDoc struct {
+	Pokemon     string           `json:"pokemon"`
+	Types       []string         `json:"types"`
+	Weaknesses  []typeMatchupDoc `json:"weaknesses"`
+	Resistances []typeMatchupDoc `json:"resistances"`
+	Immunities  []typeMatchupDoc `json:"immunities"`
+}
+
+type matchupSideDoc struct {
+	Pokemon string           `json:"pokemon"`
+	Types   []string         `json:"types"`
+	Attacks []typeMatchupDoc `json:"attacks"` // each of its types against the other pokemon
+}
+
+type matchupDoc struct {
+	Pokemon []matchupSideDoc `json:"pokemon"`
+}
+
+// the session's type chart with every one of names loaded, types are only
+// fetched the first time they're needed
+func loadTypes(conf *config, names ...string) (*typechart.Chart, error) {
+	if conf.Types == nil {
+		conf.Types = typechart.New()
+	}
+	for _, name := range names {
+		if name == "" || conf.Types.Has(name) {
+			continue
+		}
+		t, err := conf.Client.GetType(name)
+		if err != nil {
+			return nil, err
+		}
+		conf.Types.Add(t)
+	}
+	return conf.Types, nil
+}
+
+func pokemonTypes(pokemon pokeapi.Pokemon) []string {
+	types := []string{}
+	for _, t := range pokemon.Types {
+		types = append(types, t.Type.Name)
+	}
+	return types
+}
+
+func newTypeMatchupDocs(matchups []typechart.Matchup) []typeMatchupDoc {
+	docs := []typeMatchupDoc{}
+	for _, m := range matchups {
+		docs = append(docs, typeMatchupDoc{Type: m.Type, Multiplier: m.Multiplier})
+	}
+	return docs
+}
+
+// 2 -> 2x, 0.25 -> 0.25x
+func formatMultiplier(m float64) string {
+	return strconv.FormatFloat(m, 'f', -1, 64) + "x"
+}
+
+// types command shows what a pokemon is weak to, resists and is immune to
+func commandTypes(conf *config, args ...string) error {
+	pokemon, err := conf.Client.GetPokemon(args[0])
+	if err != nil {
+		return err
+	}
+	types := pokemonTypes(pokemon)
+	chart, err := loadTypes(conf, types...)
+	if err != nil {
+		return err
+	}
+
+	doc := typesDoc{
+		Pokemon:     pokemon.Name,
+		Types:       types,
+		Weaknesses:  []typeMatchupDoc{},
+		Resistances: []typeMatchupDoc{},
+		Immunities:  []typeMatchupDoc{},
+	}
+	for _, m := range newTypeMatchupDocs(chart.Defense(types)) {
+		switch {
+		case m.Multiplier > 1:
+			doc.Weaknesses = append(doc.Weaknesses, m)
+		case m.Multiplier == 0:
+			doc.Immunities = append(doc.Immunities, m)
+		default:
+			doc.Resistances = append(doc.Resistances, m)
+		}
+	}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v: %v", doc.Pokemon, strings.Join(doc.Types, "/")))
+		printMatchups(conf, "Weak to:", doc.Weaknesses)
+		printMatchups(conf, "Resists:", doc.Resistances)
+		printMatchups(conf, "Immune to:", doc.Immunities)
+	})
+}
+
+// matchup command shows how two pokemon's types fare against each other
+func commandMatchup(conf *config, args ...string) error {
+	pokemon := []pokeapi.Pokemon{}
+	types := []string{}
+	for _, name := range args {
+		p, err := conf.Client.GetPokemon(name)
+		if err != nil {
+			return err
+		}
+		pokemon = append(pokemon, p)
+		types = append(types, pokemonTypes(p)...)
+	}
+	chart, err := loadTypes(conf, types...)
+	if err != nil {
+		return err
+	}
+
+	doc := matchupDoc{}
+	for i, p := range pokemon {
+		other := pokemon[1-i]
+		doc.Pokemon = append(doc.Pokemon, matchupSideDoc{
+			Pokemon: p.Name,
+			Types:   pokemonTypes(p),
+			Attacks: newTypeMatchupDocs(chart.Offense(pokemonTypes(p), pokemonTypes(other))),
+		})
+	}
+	return conf.emit(doc, func() {
+		a, b := doc.Pokemon[0], doc.Pokemon[1]
+		fmt.Fprintln(conf.Out, fmt.Sprintf("%v (%v) vs %v (%v)", a.Pokemon, strings.Join(a.Types, "/"), b.Pokemon, strings.Join(b.Types, "/")))
+		for i, side := range doc.Pokemon {
+			printMatchups(conf, fmt.Sprintf("%v attacking %v:", side.Pokemon, doc.Pokemon[1-i].Pokemon), side.Attacks)
+		}
+	})
+}
+
+func printMatchups(conf *config, title string, matchups []typeMatchupDoc) {
+	fmt.Fprintln(conf.Out, title)
+	if len(matchups) == 0 {
+		fmt.Fprintln(conf.Out, "  . nothing")
+	}
+	for _, m := range matchups {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("  . %v: %v", m.Type, formatMultiplier(m.Multiplier)))
+	}
+}
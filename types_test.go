@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCommandTypes(t *testing.T) {
+	conf, out, api := newTestConfig(t)
+	commands := getCommands()
+
+	if err := runLine(conf, commands, "types psyduck"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out,
+		"psyduck: water",
+		"Weak to:",
+		"  . electric: 2x",
+		"  . grass: 2x",
+		"Resists:",
+		"  . fire: 0.5x",
+		"  . water: 0.5x",
+		"Immune to:",
+		"  . nothing",
+	)
+
+	if err := runLine(conf, commands, "types gyarados"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out,
+		"gyarados: water/flying",
+		"Weak to:",
+		"  . electric: 4x",
+		"Resists:",
+		"  . fire: 0.5x",
+		"  . water: 0.5x",
+		"Immune to:",
+		"  . ground: 0x",
+	)
+
+	// the chart is kept for the session, types are fetched once
+	if n := api.Requests("/type/water"); n != 1 {
+		t.Errorf("expected the water type to be fetched once, got %v", n)
+	}
+}
+
+func TestCommandMatchup(t *testing.T) {
+	conf, out, _ := newTestConfig(t)
+	commands := getCommands()
+
+	if err := runLine(conf, commands, "matchup pikachu gyarados"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out,
+		"pikachu (electric) vs gyarados (water/flying)",
+		"pikachu attacking gyarados:",
+		"  . electric: 4x",
+		"gyarados attacking pikachu:",
+		"  . water: 1x",
+		"  . flying: 0.5x",
+	)
+
+	conf.Output = outputJSON
+	if err := runLine(conf, commands, "matchup psyduck pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectLines(t, out, `{"pokemon":[{"pokemon":"psyduck","types":["water"],"attacks":[{"type":"water","multiplier":1}]},{"pokemon":"pikachu","types":["electric"],"attacks":[{"type":"electric","multiplier":2}]}]}`)
+
+	if err := runLine(conf, commands, "matchup pikachu"); err == nil {
+		t.Errorf("expected an error with only one pokemon")
+	}
+}
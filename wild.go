@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lulock/pokedex/internal/encounter"
+)
+
+// returned when something needs a current area and none has been explored
+var errNoLocation = errors.New("you're not anywhere yet, explore an area first")
+
+type wildDoc struct {
+	Area    string `json:"area"`
+	Pokemon string `json:"pokemon"`
+	Level   int    `json:"level"`
+	Method  string `json:"method"`
+}
+
+// the encounter table of the area the trainer is in
+func currentTable(conf *config) (encounter.Table, error) {
+	if conf.Location == "" {
+		return encounter.Table{}, errNoLocation
+	}
+	area, err := conf.Client.GetLocationArea(conf.Location)
+	if err != nil {
+		return encounter.Table{}, err
+	}
+	return encounter.NewTable(area), nil
+}
+
+// looks around the current area until a wild pokemon shows up
+func commandEncounter(conf *config, args ...string) error {
+	if conf.Battle != nil {
+		return errInBattle
+	}
+	table, err := currentTable(conf)
+	if err != nil {
+		return err
+	}
+	wild, ok := table.Draw(conf.Rand)
+	if !ok {
+		return fmt.Errorf("there are no pokemon around %v", conf.Location)
+	}
+	conf.Wild = &wild
+
+	doc := wildDoc{Area: conf.Location, Pokemon: wild.Pokemon, Level: wild.Level, Method: wild.Method}
+	return conf.emit(doc, func() {
+		fmt.Fprintln(conf.Out, fmt.Sprintf("A wild %v (Lv. %v) appeared!", wild.Pokemon, wild.Level))
+	})
+}
+
+// picks what catch throws at: the named pokemon if it lives in the current
+// area, otherwise whatever showed up with the encounter command
+func wildTarget(conf *config, pokename string) (encounter.Wild, error) {
+	if pokename == "" {
+		if conf.Wild == nil {
+			return encounter.Wild{}, errors.New("there's nothing to catch, try encounter or name a pokemon")
+		}
+		return *conf.Wild, nil
+	}
+	if conf.Wild != nil && conf.Wild.Pokemon == pokename {
+		return *conf.Wild, nil
+	}
+
+	table, err := currentTable(conf)
+	if err != nil {
+		return encounter.Wild{}, err
+	}
+	wild, ok := table.Meet(pokename, conf.Rand)
+	if !ok {
+		return encounter.Wild{}, fmt.Errorf("there are no %v around %v", pokename, conf.Location)
+	}
+	return wild, nil
+}
+
+// pokemon living in the current area, for completion
+func completeWildPokemon(conf *config) []string {
+	table, err := currentTable(conf)
+	if err != nil {
+		return nil
+	}
+	return table.Pokemon()
+}